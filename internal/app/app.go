@@ -7,6 +7,6 @@ import (
 // NewProgramModel constructs the Bubble Tea model for the app.
 func NewProgramModel() tea.Model {
 	styles := newStyles()
-	listModel := initList()
+	listModel := initList(styles)
 	return newModel(listModel, styles)
 }
@@ -0,0 +1,367 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	ciSuccess = "success"
+	ciFailure = "failure"
+	ciPending = "pending"
+)
+
+type checkRun struct {
+	Name       string
+	Status     string
+	Conclusion string
+	Started    time.Time
+	Completed  time.Time
+	URL        string
+}
+
+// ciState folds a check's status and conclusion into success, failure or
+// pending.
+func (c checkRun) ciState() string {
+	if c.Status != "" && c.Status != "completed" {
+		return ciPending
+	}
+	switch c.Conclusion {
+	case "success", "neutral", "skipped":
+		return ciSuccess
+	case "":
+		return ciPending
+	default:
+		return ciFailure
+	}
+}
+
+func (c checkRun) duration() time.Duration {
+	if c.Started.IsZero() {
+		return 0
+	}
+	end := c.Completed
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(c.Started)
+}
+
+func summarizeChecks(checks []checkRun) string {
+	if len(checks) == 0 {
+		return ""
+	}
+	state := ciSuccess
+	for _, c := range checks {
+		switch c.ciState() {
+		case ciFailure:
+			return ciFailure
+		case ciPending:
+			state = ciPending
+		}
+	}
+	return state
+}
+
+func firstFailingCheck(checks []checkRun) (checkRun, bool) {
+	for _, c := range checks {
+		if c.ciState() == ciFailure && c.URL != "" {
+			return c, true
+		}
+	}
+	return checkRun{}, false
+}
+
+// fetchCommitChecks merges the legacy combined status contexts and the check
+// runs reported for a commit.
+func fetchCommitChecks(ctx context.Context, token, repo, sha string) ([]checkRun, error) {
+	if sha == "" {
+		return nil, nil
+	}
+	statuses, err := fetchCombinedStatus(ctx, token, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := fetchCheckRuns(ctx, token, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	checks := append(statuses, runs...)
+	sort.SliceStable(checks, func(i, j int) bool {
+		return strings.ToLower(checks[i].Name) < strings.ToLower(checks[j].Name)
+	})
+	return checks, nil
+}
+
+func fetchCombinedStatus(ctx context.Context, token, repo, sha string) ([]checkRun, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s/status", repo, sha)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, readAPIError(resp)
+	}
+
+	var payload struct {
+		Statuses []struct {
+			Context   string    `json:"context"`
+			State     string    `json:"state"`
+			TargetURL string    `json:"target_url"`
+			CreatedAt time.Time `json:"created_at"`
+			UpdatedAt time.Time `json:"updated_at"`
+		} `json:"statuses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	checks := make([]checkRun, 0, len(payload.Statuses))
+	for _, s := range payload.Statuses {
+		c := checkRun{
+			Name:    sanitizeLine(s.Context),
+			Status:  "completed",
+			Started: s.CreatedAt,
+			URL:     sanitizeLine(s.TargetURL),
+		}
+		switch s.State {
+		case "pending":
+			c.Status = "in_progress"
+		case "error":
+			c.Conclusion = "failure"
+			c.Completed = s.UpdatedAt
+		default:
+			c.Conclusion = sanitizeLine(s.State)
+			c.Completed = s.UpdatedAt
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+func fetchCheckRuns(ctx context.Context, token, repo, sha string) ([]checkRun, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s/check-runs?per_page=100", repo, sha)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, readAPIError(resp)
+	}
+
+	var payload struct {
+		CheckRuns []struct {
+			Name        string     `json:"name"`
+			Status      string     `json:"status"`
+			Conclusion  string     `json:"conclusion"`
+			StartedAt   *time.Time `json:"started_at"`
+			CompletedAt *time.Time `json:"completed_at"`
+			HTMLURL     string     `json:"html_url"`
+			DetailsURL  string     `json:"details_url"`
+		} `json:"check_runs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	checks := make([]checkRun, 0, len(payload.CheckRuns))
+	for _, r := range payload.CheckRuns {
+		c := checkRun{
+			Name:       sanitizeLine(r.Name),
+			Status:     sanitizeLine(r.Status),
+			Conclusion: sanitizeLine(r.Conclusion),
+			URL:        sanitizeLine(r.HTMLURL),
+		}
+		if r.DetailsURL != "" {
+			c.URL = sanitizeLine(r.DetailsURL)
+		}
+		if r.StartedAt != nil {
+			c.Started = *r.StartedAt
+		}
+		if r.CompletedAt != nil {
+			c.Completed = *r.CompletedAt
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// ciHead is the CI summary of a pull request's head commit.
+type ciHead struct {
+	SHA   string
+	State string
+}
+
+// rollupState maps a GraphQL status check rollup onto success, failure or
+// pending; commits without any checks have no state.
+func rollupState(state string) string {
+	switch state {
+	case "SUCCESS":
+		return ciSuccess
+	case "FAILURE", "ERROR":
+		return ciFailure
+	case "PENDING", "EXPECTED":
+		return ciPending
+	}
+	return ""
+}
+
+// ciRollupQuery asks for the head commit and its check rollup of every
+// pull request in one query, aliased pr0, pr1, ...
+func ciRollupQuery(items []issueItem) (string, map[string]any) {
+	var params []string
+	var fields strings.Builder
+	variables := make(map[string]any)
+	for i, item := range items {
+		owner, name, _ := strings.Cut(item.Repo, "/")
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!, $p%d: Int!", i, i, i))
+		variables[fmt.Sprintf("o%d", i)] = owner
+		variables[fmt.Sprintf("n%d", i)] = name
+		variables[fmt.Sprintf("p%d", i)] = item.Number
+		fmt.Fprintf(&fields, "  pr%d: repository(owner: $o%d, name: $n%d) { pullRequest(number: $p%d) { headRefOid commits(last: 1) { nodes { commit { statusCheckRollup { state } } } } } }\n", i, i, i, i)
+	}
+	return fmt.Sprintf("query(%s) {\n%s}", strings.Join(params, ", "), fields.String()), variables
+}
+
+// fetchCIStates resolves the head commit and CI summary of every pull
+// request in items with a single GraphQL query, keyed by item URL. Items
+// whose checks cannot be loaded (for example a repository behind SSO) are
+// left out rather than failing the rest.
+func fetchCIStates(ctx context.Context, token string, items []issueItem) map[string]ciHead {
+	var prs []issueItem
+	for _, item := range items {
+		if item.Kind == "PR" && strings.Contains(item.Repo, "/") && item.Number > 0 {
+			prs = append(prs, item)
+		}
+	}
+	heads := make(map[string]ciHead)
+	if len(prs) == 0 {
+		return heads
+	}
+
+	query, variables := ciRollupQuery(prs)
+	data, _, err := postGraphQLPartial(ctx, token, query, variables)
+	if err != nil || len(data) == 0 {
+		return heads
+	}
+	var payload map[string]*struct {
+		PullRequest *struct {
+			HeadRefOid string `json:"headRefOid"`
+			Commits    struct {
+				Nodes []struct {
+					Commit struct {
+						StatusCheckRollup *struct {
+							State string `json:"state"`
+						} `json:"statusCheckRollup"`
+					} `json:"commit"`
+				} `json:"nodes"`
+			} `json:"commits"`
+		} `json:"pullRequest"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return heads
+	}
+	for i, item := range prs {
+		repo := payload[fmt.Sprintf("pr%d", i)]
+		if repo == nil || repo.PullRequest == nil {
+			continue
+		}
+		head := ciHead{SHA: repo.PullRequest.HeadRefOid}
+		if nodes := repo.PullRequest.Commits.Nodes; len(nodes) > 0 && nodes[0].Commit.StatusCheckRollup != nil {
+			head.State = rollupState(nodes[0].Commit.StatusCheckRollup.State)
+		}
+		heads[item.URL] = head
+	}
+	return heads
+}
+
+// ciSeen remembers the head commit a pull request had at a given update
+// time. Pushing updates the pull request, so while its update time is
+// unchanged the head commit is too.
+type ciSeen struct {
+	SHA     string
+	Updated time.Time
+}
+
+// checksCmd loads CI states for the listed pull requests, skipping those
+// whose head commit is known to have passed. Only passing commits are
+// cached: pending ones move on, and failed checks are often re-run.
+func (m *model) checksCmd() tea.Cmd {
+	var stale []issueItem
+	for i, item := range m.items {
+		if item.Kind != "PR" {
+			continue
+		}
+		if seen, ok := m.ciSeen[item.URL]; ok && seen.Updated.Equal(item.Updated) {
+			if state, ok := m.ciCache[seen.SHA]; ok {
+				m.items[i].CI = state
+				continue
+			}
+		}
+		stale = append(stale, item)
+	}
+	if len(stale) == 0 {
+		m.refreshList()
+		return nil
+	}
+	return fetchChecksCmd(stale)
+}
+
+func (m *model) applyChecks(msg checksResult) {
+	if m.ciSeen == nil {
+		m.ciSeen = make(map[string]ciSeen)
+		m.ciCache = make(map[string]string)
+	}
+	for url, head := range msg.heads {
+		if head.SHA == "" {
+			continue
+		}
+		m.ciSeen[url] = ciSeen{SHA: head.SHA, Updated: msg.updated[url]}
+		if head.State == ciSuccess {
+			m.ciCache[head.SHA] = head.State
+		}
+	}
+	for i := range m.items {
+		if head, ok := msg.heads[m.items[i].URL]; ok {
+			m.items[i].CI = head.State
+		}
+	}
+	m.refreshList()
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
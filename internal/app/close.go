@@ -0,0 +1,442 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	closeReasonCompleted  = "completed"
+	closeReasonNotPlanned = "not_planned"
+	closeReasonDuplicate  = "duplicate"
+)
+
+// undoWindow is how long a close or reopen waits before it is sent, and
+// how long afterwards it can still be reverted.
+const undoWindow = 5 * time.Second
+
+// stateChange closes or reopens an item, optionally commenting first.
+// Revert lists comments to delete, used when undoing a close that posted
+// them; Posted is filled in with the comments this change created. Seq ties
+// the result back to the pending change that sent it. EditorFile is the
+// $EDITOR file the comment was written in, kept until the change succeeds.
+type stateChange struct {
+	Seq         int
+	Item        issueItem
+	State       string
+	Reason      string
+	DuplicateOf int
+	Comment     string
+	EditorFile  string
+	Revert      []int64
+	Posted      []int64
+	Undo        bool
+}
+
+// pendingStateChange tracks a state change through its undo window.
+type pendingStateChange struct {
+	change        stateChange
+	seq           int
+	sent          bool
+	done          bool
+	undoRequested bool
+}
+
+type stateTickMsg struct {
+	seq int
+}
+
+type undoExpiredMsg struct {
+	seq int
+}
+
+var closeReasons = []string{closeReasonCompleted, closeReasonNotPlanned, closeReasonDuplicate}
+
+func closeReasonLabel(reason string) string {
+	switch reason {
+	case closeReasonNotPlanned:
+		return "not planned"
+	case closeReasonDuplicate:
+		return "duplicate"
+	case closeReasonCompleted:
+		return "completed"
+	}
+	return ""
+}
+
+func nextCloseReason(reason string) string {
+	for i, r := range closeReasons {
+		if r == reason {
+			return closeReasons[(i+1)%len(closeReasons)]
+		}
+	}
+	return closeReasons[0]
+}
+
+// createIssueComment posts a comment and returns its id so it can be
+// deleted again by an undo.
+func createIssueComment(ctx context.Context, token string, item issueItem, body string) (int64, error) {
+	raw, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return 0, err
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/comments", item.Repo, item.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return 0, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, readAPIError(resp)
+	}
+	var payload struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return 0, err
+	}
+	return payload.ID, nil
+}
+
+// setIssueState patches the state of an issue or pull request. The reason
+// only applies to issues; pull requests have none.
+func setIssueState(ctx context.Context, token string, item issueItem, state, reason string) error {
+	payload := map[string]string{"state": state}
+	if reason != "" && item.Kind != "PR" {
+		payload["state_reason"] = reason
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d", item.Repo, item.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
+
+// applyStateChange removes reverted comments, posts the duplicate marker
+// and closing comment, then changes the state. A duplicate is closed as
+// "not planned"; the "Duplicate of #N" comment is what makes GitHub link
+// and label it as a duplicate.
+func applyStateChange(ctx context.Context, token string, change stateChange) (stateChange, error) {
+	if change.Item.Repo == "" || change.Item.Number == 0 {
+		return change, errors.New("missing repo or number")
+	}
+	for _, id := range change.Revert {
+		if err := deleteIssueComment(ctx, token, change.Item.Repo, id); err != nil {
+			return change, err
+		}
+	}
+	var comments []string
+	if change.DuplicateOf > 0 {
+		comments = append(comments, fmt.Sprintf("Duplicate of #%d", change.DuplicateOf))
+	}
+	if strings.TrimSpace(change.Comment) != "" {
+		comments = append(comments, change.Comment)
+	}
+	for _, body := range comments {
+		id, err := createIssueComment(ctx, token, change.Item, body)
+		if err != nil {
+			return change, removePosted(ctx, token, &change, err)
+		}
+		change.Posted = append(change.Posted, id)
+	}
+
+	reason := change.Reason
+	if reason == closeReasonDuplicate {
+		reason = closeReasonNotPlanned
+	}
+	if err := setIssueState(ctx, token, change.Item, change.State, reason); err != nil {
+		return change, removePosted(ctx, token, &change, err)
+	}
+	return change, nil
+}
+
+// removePosted deletes the comments a failed change already posted, so an
+// item that stays open is not left saying it was closed. Comments that
+// cannot be deleted are named in the returned error.
+func removePosted(ctx context.Context, token string, change *stateChange, cause error) error {
+	var left []string
+	for _, id := range change.Posted {
+		if err := deleteIssueComment(ctx, token, change.Item.Repo, id); err != nil {
+			left = append(left, strconv.FormatInt(id, 10))
+		}
+	}
+	change.Posted = nil
+	if len(left) > 0 {
+		return fmt.Errorf("%w (comments %s were left on %s#%d)", cause, strings.Join(left, ", "), change.Item.Repo, change.Item.Number)
+	}
+	return cause
+}
+
+// describeStateChange says what a change does, e.g. "Close o/r#1 as not
+// planned".
+func describeStateChange(change stateChange) string {
+	verb := "Reopen"
+	if change.State == "closed" {
+		verb = "Close"
+	}
+	text := fmt.Sprintf("%s %s#%d", verb, change.Item.Repo, change.Item.Number)
+	switch {
+	case change.DuplicateOf > 0:
+		text += fmt.Sprintf(" as duplicate of #%d", change.DuplicateOf)
+	case change.State == "closed" && change.Item.Kind != "PR" && change.Reason != "":
+		text += " as " + closeReasonLabel(change.Reason)
+	}
+	return text
+}
+
+func (m *model) startClose(item issueItem) {
+	m.commentMode = true
+	m.composeKind = composeClose
+	m.actionItem = item
+	m.closeReason = ""
+	if item.Kind != "PR" {
+		m.closeReason = closeReasonCompleted
+	}
+	m.textarea.Focus()
+	m.textarea.SetValue("")
+}
+
+// submitClose takes the closing comment from the composer. Duplicates
+// first ask which issue they duplicate.
+func (m *model) submitClose(body string) tea.Cmd {
+	if m.stateChangeBusy() {
+		return nil
+	}
+	m.commentMode = false
+	m.textarea.Blur()
+	m.pendingBody = body
+	if m.closeReason != closeReasonDuplicate {
+		return m.scheduleClose(stateChange{
+			Item:   m.actionItem,
+			State:  "closed",
+			Reason: m.closeReason,
+		})
+	}
+
+	m.picker = newPicker("Duplicate of", m.duplicateOptions(), nil, false)
+	m.picker.freeText = true
+	m.picker.input.Placeholder = "Issue number or search..."
+	m.pickerKind = pickDuplicate
+	m.pickerMode = true
+	if _, cached := m.repoRefs[m.actionItem.Repo]; !cached {
+		return m.loadCompletionSource(m.actionItem.Repo, '#')
+	}
+	return nil
+}
+
+// duplicateOptions offers the repository's recent issues and pull requests
+// as originals, once the # completion source has loaded them.
+func (m model) duplicateOptions() []pickerOption {
+	var options []pickerOption
+	for _, r := range m.repoRefs[m.actionItem.Repo] {
+		if r.Number == m.actionItem.Number {
+			continue
+		}
+		options = append(options, pickerOption{
+			Value: strconv.Itoa(r.Number),
+			Label: fmt.Sprintf("#%d %s", r.Number, r.Title),
+			Hint:  strings.ToLower(r.Kind) + " • " + r.State,
+		})
+	}
+	return options
+}
+
+// submitDuplicate closes the item as a duplicate of the chosen issue. A
+// typed number wins over the highlighted suggestion.
+func (m *model) submitDuplicate() tea.Cmd {
+	if m.stateChangeBusy() {
+		m.pickerMode = true
+		return nil
+	}
+	value := strings.TrimPrefix(m.picker.Query(), "#")
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		option, ok := m.picker.current()
+		if !ok {
+			m.picker.errorMsg = "Enter the number of the original issue"
+			m.pickerMode = true
+			return nil
+		}
+		number, _ = strconv.Atoi(option.Value)
+	}
+	if number == m.actionItem.Number {
+		m.picker.errorMsg = "An issue cannot duplicate itself"
+		m.pickerMode = true
+		return nil
+	}
+	return m.scheduleClose(stateChange{
+		Item:        m.actionItem,
+		State:       "closed",
+		Reason:      closeReasonDuplicate,
+		DuplicateOf: number,
+	})
+}
+
+// scheduleClose schedules a close written in the composer. The comment and
+// its $EDITOR file travel with the change, so a failed close can give them
+// back.
+func (m *model) scheduleClose(change stateChange) tea.Cmd {
+	change.Comment = m.pendingBody
+	change.EditorFile = m.editorFile
+	m.editorFile = ""
+	m.textarea.SetValue("")
+	return m.scheduleStateChange(change)
+}
+
+// settleStateText removes the $EDITOR file of a close that went through.
+// When it failed, the file is kept and the comment goes back into the
+// close composer, unless another composer or prompt took over meanwhile.
+func (m *model) settleStateText(msg stateResult) string {
+	change := msg.change
+	if change.Undo {
+		return ""
+	}
+	kept := m.settleFile(change.EditorFile, msg.err)
+	if msg.err == nil || change.Comment == "" || m.commentMode || m.confirmMode || m.pickerMode {
+		return kept
+	}
+	m.actionItem = change.Item
+	m.closeReason = change.Reason
+	m.commentMode = true
+	m.composeKind = composeClose
+	m.textarea.Focus()
+	m.textarea.SetValue(change.Comment)
+	return kept
+}
+
+// stateChangeBusy reports, in the status line, a change still waiting for
+// GitHub's answer. Another one has to wait, or an undo could revert the
+// wrong item.
+func (m *model) stateChangeBusy() bool {
+	p := m.pendingState
+	if p == nil || !p.sent || p.done {
+		return false
+	}
+	m.status = fmt.Sprintf("Wait for %s to finish", describeStateChange(p.change))
+	m.statusOverride = true
+	return true
+}
+
+// scheduleStateChange holds a change for the undo window before sending.
+// A change still waiting in its window is sent right away instead of being
+// dropped; only the newest change can be undone.
+func (m *model) scheduleStateChange(change stateChange) tea.Cmd {
+	var flush tea.Cmd
+	if p := m.pendingState; p != nil && !p.sent {
+		flush = updateIssueStateCmd(p.change)
+	}
+	m.stateSeq++
+	change.Seq = m.stateSeq
+	m.pendingState = &pendingStateChange{change: change, seq: m.stateSeq}
+	m.status = fmt.Sprintf("%s in %ds • u to undo", describeStateChange(change), int(undoWindow/time.Second))
+	m.statusOverride = true
+	seq := m.stateSeq
+	return tea.Batch(flush, tea.Tick(undoWindow, func(time.Time) tea.Msg {
+		return stateTickMsg{seq: seq}
+	}))
+}
+
+func (m *model) handleStateTick(msg stateTickMsg) tea.Cmd {
+	p := m.pendingState
+	if p == nil || p.seq != msg.seq || p.sent {
+		return nil
+	}
+	p.sent = true
+	m.actionItem = p.change.Item
+	m.actionLoading = true
+	m.status = actionProgress(p.change.State)
+	m.statusOverride = true
+	return updateIssueStateCmd(p.change)
+}
+
+// undoStateChange cancels a change still in its window, or reverts one
+// that already reached GitHub.
+func (m *model) undoStateChange() tea.Cmd {
+	p := m.pendingState
+	if p == nil {
+		return nil
+	}
+	switch {
+	case !p.sent:
+		m.pendingState = nil
+		m.status = "Undone: " + describeStateChange(p.change) + " was not sent"
+		m.statusOverride = true
+		m.settleFile(p.change.EditorFile, nil)
+		return nil
+	case !p.done:
+		p.undoRequested = true
+		m.status = "Will undo once GitHub answers..."
+		m.statusOverride = true
+		return nil
+	}
+	return m.revertStateChange(p.change)
+}
+
+func (m *model) revertStateChange(change stateChange) tea.Cmd {
+	m.pendingState = nil
+	reverse := stateChange{Item: change.Item, State: "closed", Revert: change.Posted, Undo: true}
+	if change.State == "closed" {
+		reverse.State = "open"
+		reverse.Reason = "reopened"
+	}
+	m.actionItem = change.Item
+	m.actionLoading = true
+	m.status = "Undoing: " + strings.ToLower(actionProgress(reverse.State))
+	m.statusOverride = true
+	return updateIssueStateCmd(reverse)
+}
+
+// finishStateChange opens the post-send undo window after a successful
+// change and carries out an undo requested while it was in flight.
+func (m *model) finishStateChange(msg stateResult) tea.Cmd {
+	p := m.pendingState
+	if p == nil || msg.change.Undo || msg.change.Seq != p.seq {
+		return nil
+	}
+	if msg.err != nil {
+		m.pendingState = nil
+		return nil
+	}
+	p.change = msg.change
+	p.done = true
+	if p.undoRequested {
+		return m.revertStateChange(p.change)
+	}
+	m.status += " • u to undo"
+	seq := p.seq
+	return tea.Tick(undoWindow, func(time.Time) tea.Msg {
+		return undoExpiredMsg{seq: seq}
+	})
+}
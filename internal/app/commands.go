@@ -31,17 +31,62 @@ func fetchCmd(f filter, kind string) tea.Cmd {
 	}
 }
 
+func fetchChecksCmd(items []issueItem) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		updated := make(map[string]time.Time, len(items))
+		for _, item := range items {
+			updated[item.URL] = item.Updated
+		}
+		return checksResult{heads: fetchCIStates(ctx, token, items), updated: updated}
+	}
+}
+
 func fetchDetailCmd(item issueItem, commentPage int) tea.Cmd {
 	return func() tea.Msg {
 		token := os.Getenv("GITHUB_TOKEN")
 		if token == "" {
-			return detailResult{err: errors.New("GITHUB_TOKEN is required")}
+			return detailResult{target: item, err: errors.New("GITHUB_TOKEN is required")}
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
 		result, err := fetchIssueDetail(ctx, token, item, commentPage)
-		return detailResult{item: result, err: err}
+		return detailResult{target: item, item: result, err: err}
+	}
+}
+
+func fetchTimelineCmd(item issueItem, page int) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return timelineResult{target: item, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		events, page, err := fetchTimeline(ctx, token, item, page)
+		return timelineResult{target: item, events: events, page: page, err: err}
+	}
+}
+
+func fetchDiffCmd(item issueItem) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return diffResult{target: item, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		files, err := fetchPullRequestFiles(ctx, token, item)
+		return diffResult{target: item, files: files, err: err}
 	}
 }
 
@@ -85,43 +130,206 @@ func postCommentCmd(item issueItem, body string) tea.Cmd {
 	}
 }
 
-func updateIssueStateCmd(item issueItem, state string) tea.Cmd {
+func replyThreadCmd(item issueItem, thread reviewThread, body string) tea.Cmd {
 	return func() tea.Msg {
 		token := os.Getenv("GITHUB_TOKEN")
 		if token == "" {
-			return stateResult{err: errors.New("GITHUB_TOKEN is required")}
+			return commentResult{err: errors.New("GITHUB_TOKEN is required")}
 		}
-		if item.Repo == "" || item.Number == 0 {
-			return stateResult{err: errors.New("missing repo or number")}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		return commentResult{err: replyToThread(ctx, token, item, thread, body)}
+	}
+}
+
+func submitReviewCmd(item issueItem, event, body string, comments []pendingComment) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return reviewResult{event: event, err: errors.New("GITHUB_TOKEN is required")}
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		return reviewResult{event: event, err: submitReview(ctx, token, item, event, body, comments)}
+	}
+}
 
+func fetchMergeOptionsCmd(repo string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return mergeOptionsResult{repo: repo, err: errors.New("GITHUB_TOKEN is required")}
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		payload := map[string]string{"state": state}
-		raw, err := json.Marshal(payload)
-		if err != nil {
-			return stateResult{err: err}
+		options, err := fetchMergeOptions(ctx, token, repo)
+		return mergeOptionsResult{repo: repo, options: options, err: err}
+	}
+}
+
+func mergeCmd(item detail, method, title, message string, deleteBranch bool) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return mergeResult{method: method, err: errors.New("GITHUB_TOKEN is required")}
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
 
-		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d", item.Repo, item.Number)
-		req, err := http.NewRequestWithContext(ctx, http.MethodPatch, apiURL, strings.NewReader(string(raw)))
-		if err != nil {
-			return stateResult{err: err}
+		outcome, err := mergePullRequest(ctx, token, item, method, title, message, deleteBranch)
+		return mergeResult{method: method, outcome: outcome, err: err}
+	}
+}
+
+func setAutoMergeCmd(item detail, method, title, message string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return autoMergeResult{method: method, err: errors.New("GITHUB_TOKEN is required")}
 		}
-		addJSONHeaders(req, token)
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
 
-		resp, err := http.DefaultClient.Do(req)
+		return autoMergeResult{method: method, err: setAutoMerge(ctx, token, item, method, title, message)}
+	}
+}
+
+func fetchLabelsCmd(repo string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return labelsResult{repo: repo, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		labels, err := fetchRepoLabels(ctx, token, repo)
+		return labelsResult{repo: repo, labels: labels, err: err}
+	}
+}
+
+func setLabelsCmd(item issueItem, names []string, previous []issueLabel) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return labelsSetResult{target: item, previous: previous, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		labels, err := setIssueLabels(ctx, token, item, names)
+		return labelsSetResult{target: item, labels: labels, previous: previous, err: err}
+	}
+}
+
+func viewerCmd() tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return viewerResult{err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		login, err := fetchViewer(ctx, token)
+		return viewerResult{login: login, err: err}
+	}
+}
+
+func fetchPeopleCmd(repo string, withTeams bool) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return peopleResult{repo: repo, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		users, err := fetchAssignableUsers(ctx, token, repo)
 		if err != nil {
-			return stateResult{err: err}
+			return peopleResult{repo: repo, err: err}
 		}
-		defer resp.Body.Close()
+		var teams []string
+		if withTeams {
+			teams, _ = fetchRepoTeams(ctx, token, repo)
+			if teams == nil {
+				teams = []string{}
+			}
+		}
+		return peopleResult{repo: repo, users: users, teams: teams}
+	}
+}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return stateResult{err: readAPIError(resp)}
+func editAssigneesCmd(item issueItem, add, remove []string, message string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return peopleUpdatedResult{target: item, err: errors.New("GITHUB_TOKEN is required")}
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		err := editAssignees(ctx, token, item, add, remove)
+		return peopleUpdatedResult{target: item, message: message, err: err}
+	}
+}
+
+func editReviewersCmd(item issueItem, change reviewerChange, message string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return peopleUpdatedResult{target: item, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		err := editReviewers(ctx, token, item, change)
+		return peopleUpdatedResult{target: item, message: message, err: err}
+	}
+}
+
+func editCommentCmd(item issueItem, id int64, body string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return commentEditResult{err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		err := editIssueComment(ctx, token, item.Repo, id, body)
+		return commentEditResult{err: err}
+	}
+}
+
+func deleteCommentCmd(item issueItem, id int64) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return commentEditResult{deleted: true, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		err := deleteIssueComment(ctx, token, item.Repo, id)
+		return commentEditResult{deleted: true, err: err}
+	}
+}
+
+func updateIssueStateCmd(change stateChange) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return stateResult{state: change.State, change: change, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
 
-		return stateResult{state: state}
+		change, err := applyStateChange(ctx, token, change)
+		return stateResult{state: change.State, change: change, err: err}
 	}
 }
 
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editIssueComment replaces the body of an issue or pull request comment.
+func editIssueComment(ctx context.Context, token, repo string, id int64, body string) error {
+	if repo == "" || id == 0 {
+		return errors.New("missing repo or comment id")
+	}
+	raw, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/comments/%d", repo, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
+
+func deleteIssueComment(ctx context.Context, token, repo string, id int64) error {
+	if repo == "" || id == 0 {
+		return errors.New("missing repo or comment id")
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/comments/%d", repo, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
+
+func commentIndex(comments []issueComment, id int64) int {
+	for i, c := range comments {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// stepSelection moves the detail cursor through review threads and then
+// the comments on the current page. Stepping past either end clears it.
+func (m *model) stepSelection(forward bool) {
+	threads := m.detailItem.Threads
+	var comments []issueComment
+	if m.detailSection == sectionComments {
+		comments = m.detailItem.CommentList
+	}
+	total := len(threads) + len(comments)
+	if total == 0 {
+		return
+	}
+
+	i := -1
+	if t := threadIndex(threads, m.selectedThread); t >= 0 {
+		i = t
+	} else if c := commentIndex(comments, m.selectedComment); c >= 0 {
+		i = len(threads) + c
+	}
+	switch {
+	case i < 0 && forward:
+		i = 0
+	case i < 0:
+		i = total - 1
+	case forward:
+		i++
+	default:
+		i--
+	}
+
+	m.selectedThread = ""
+	m.selectedComment = 0
+	switch {
+	case i < 0 || i >= total:
+	case i < len(threads):
+		m.selectedThread = threads[i].ID
+	default:
+		m.selectedComment = comments[i-len(threads)].ID
+	}
+	m.syncDetailViewport()
+	m.revealLine(m.selectionAnchor())
+}
+
+// ownSelectedComment returns the selected comment if the viewer wrote it,
+// explaining in the status line why not otherwise.
+func (m *model) ownSelectedComment() (issueComment, tea.Cmd, bool) {
+	i := commentIndex(m.detailItem.CommentList, m.selectedComment)
+	if i < 0 {
+		return issueComment{}, nil, false
+	}
+	if m.viewer == "" {
+		m.status = "Your GitHub login is not known yet"
+		m.statusOverride = true
+		return issueComment{}, viewerCmd(), false
+	}
+	comment := m.detailItem.CommentList[i]
+	if comment.Author != m.viewer {
+		m.status = "You can only change your own comments"
+		m.statusOverride = true
+		return issueComment{}, nil, false
+	}
+	return comment, nil, true
+}
+
+func (m *model) startEditComment() tea.Cmd {
+	comment, cmd, ok := m.ownSelectedComment()
+	if !ok {
+		return cmd
+	}
+	m.commentMode = true
+	m.composeKind = composeEditComment
+	m.editingComment = comment.ID
+	m.actionItem = m.detailTarget
+	m.textarea.Focus()
+	m.textarea.SetValue(comment.Body)
+	return nil
+}
+
+func (m *model) startDeleteComment() tea.Cmd {
+	comment, cmd, ok := m.ownSelectedComment()
+	if !ok {
+		return cmd
+	}
+	m.confirmMode = true
+	m.confirmAction = confirmDeleteComment
+	m.editingComment = comment.ID
+	m.actionItem = m.detailTarget
+	return nil
+}
+
+// quoteReply builds the composer text for answering a comment: the comment
+// as a markdown block quote followed by an @mention of its author.
+func quoteReply(comment issueComment) string {
+	lines := strings.Split(strings.TrimSpace(comment.Body), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ">"
+			continue
+		}
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n") + "\n\n@" + comment.Author + " "
+}
+
+func (m *model) startQuoteReply() {
+	i := commentIndex(m.detailItem.CommentList, m.selectedComment)
+	if i < 0 {
+		return
+	}
+	m.commentMode = true
+	m.composeKind = composeQuoteReply
+	m.editingComment = m.selectedComment
+	m.actionItem = m.detailTarget
+	m.textarea.Focus()
+	m.restoreDraft(quoteReply(m.detailItem.CommentList[i]))
+}
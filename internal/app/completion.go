@@ -0,0 +1,380 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sahilm/fuzzy"
+)
+
+const maxCompletions = 6
+
+// refSuggestion is an issue or pull request offered after "#".
+type refSuggestion struct {
+	Number int
+	Title  string
+	Kind   string
+	State  string
+}
+
+type completionItem struct {
+	Insert string
+	Label  string
+	Hint   string
+}
+
+// completion is the popup shown while the word under the composer cursor
+// starts with @ or #.
+type completion struct {
+	trigger rune
+	query   string
+	items   []completionItem
+	index   int
+}
+
+type completionSourceResult struct {
+	repo    string
+	trigger rune
+	users   []string
+	refs    []refSuggestion
+	err     error
+}
+
+// fetchRecentRefs lists the most recently updated issues and pull requests
+// of a repository.
+func fetchRecentRefs(ctx context.Context, token, repo string) ([]refSuggestion, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues?state=all&sort=updated&direction=desc&per_page=100", repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, readAPIError(resp)
+	}
+
+	var payload []struct {
+		Number      int       `json:"number"`
+		Title       string    `json:"title"`
+		State       string    `json:"state"`
+		PullRequest *struct{} `json:"pull_request"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	refs := make([]refSuggestion, 0, len(payload))
+	for _, p := range payload {
+		kind := "Issue"
+		if p.PullRequest != nil {
+			kind = "PR"
+		}
+		refs = append(refs, refSuggestion{
+			Number: p.Number,
+			Title:  sanitizeLine(p.Title),
+			Kind:   kind,
+			State:  sanitizeLine(p.State),
+		})
+	}
+	return refs, nil
+}
+
+func fetchCompletionSourceCmd(repo string, trigger rune) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return completionSourceResult{repo: repo, trigger: trigger, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		if trigger == '#' {
+			refs, err := fetchRecentRefs(ctx, token, repo)
+			return completionSourceResult{repo: repo, trigger: trigger, refs: refs, err: err}
+		}
+		users, err := fetchAssignableUsers(ctx, token, repo)
+		return completionSourceResult{repo: repo, trigger: trigger, users: users, err: err}
+	}
+}
+
+// completionToken finds an @mention or #reference being typed right
+// before the cursor. The trigger must start a word, so e-mail addresses
+// and anchors like "a#b" are left alone.
+func completionToken(line []rune, col int) (rune, string, bool) {
+	col = min(col, len(line))
+	start := col
+	for start > 0 {
+		r := line[start-1]
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '/' {
+			start--
+			continue
+		}
+		break
+	}
+	if start == 0 {
+		return 0, "", false
+	}
+	trigger := line[start-1]
+	if trigger != '@' && trigger != '#' {
+		return 0, "", false
+	}
+	if start >= 2 {
+		if before := line[start-2]; unicode.IsLetter(before) || unicode.IsDigit(before) || before == '`' {
+			return 0, "", false
+		}
+	}
+	return trigger, string(line[start:col]), true
+}
+
+// cursorToken returns the completion token at the textarea cursor.
+func (m model) cursorToken() (rune, string, bool) {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row := m.textarea.Line()
+	if row < 0 || row >= len(lines) {
+		return 0, "", false
+	}
+	info := m.textarea.LineInfo()
+	return completionToken([]rune(lines[row]), info.StartColumn+info.ColumnOffset)
+}
+
+// mentionCandidates lists people for @: everyone already in the
+// conversation first, then the repository's assignable users.
+func (m model) mentionCandidates() []string {
+	seen := make(map[string]bool)
+	var users []string
+	add := func(login string) {
+		if login != "" && login != "ghost" && !seen[login] {
+			seen[login] = true
+			users = append(users, login)
+		}
+	}
+	if m.detailItem.URL == m.actionItem.URL {
+		add(m.detailItem.Author)
+		for _, c := range m.detailItem.CommentList {
+			add(c.Author)
+		}
+		for _, t := range m.detailItem.Threads {
+			for _, c := range t.Comments {
+				add(c.Author)
+			}
+		}
+		for _, login := range m.detailItem.Assignees {
+			add(login)
+		}
+		for _, r := range m.detailItem.Reviewers {
+			if !r.Team {
+				add(r.Login)
+			}
+		}
+	} else {
+		add(m.actionItem.Author)
+	}
+	for _, login := range m.repoAssignees[m.actionItem.Repo] {
+		add(login)
+	}
+	return users
+}
+
+// updateCompletion recomputes the popup after the composer text or cursor
+// changed, fetching the repository's suggestions on first use.
+func (m *model) updateCompletion() tea.Cmd {
+	trigger, query, ok := m.cursorToken()
+	if !ok || m.completionDismissed == query+string(trigger) {
+		m.clearCompletion()
+		return nil
+	}
+	m.completionDismissed = ""
+	repo := m.actionItem.Repo
+
+	var cmd tea.Cmd
+	var items []completionItem
+	if trigger == '@' {
+		if _, cached := m.repoAssignees[repo]; !cached && repo != "" && m.completionSources[repo+"@"] == "" {
+			cmd = m.loadCompletionSource(repo, trigger)
+		}
+		users := m.mentionCandidates()
+		for _, i := range fuzzyOrder(query, users) {
+			items = append(items, completionItem{Insert: users[i], Label: "@" + users[i]})
+		}
+	} else {
+		refs, cached := m.repoRefs[repo]
+		if !cached && repo != "" && m.completionSources[repo+"#"] == "" {
+			cmd = m.loadCompletionSource(repo, trigger)
+		}
+		labels := make([]string, len(refs))
+		for i, r := range refs {
+			labels[i] = strconv.Itoa(r.Number) + " " + r.Title
+		}
+		for _, i := range fuzzyOrder(query, labels) {
+			r := refs[i]
+			items = append(items, completionItem{
+				Insert: strconv.Itoa(r.Number),
+				Label:  fmt.Sprintf("#%d %s", r.Number, r.Title),
+				Hint:   strings.ToLower(r.Kind) + " • " + r.State,
+			})
+		}
+	}
+
+	index := 0
+	if m.completion != nil && m.completion.trigger == trigger {
+		index = min(m.completion.index, max(0, len(items)-1))
+	}
+	m.completion = &completion{trigger: trigger, query: query, items: items, index: index}
+	m.sizeComposer()
+	return cmd
+}
+
+func (m *model) clearCompletion() {
+	if m.completion == nil {
+		return
+	}
+	m.completion = nil
+	m.sizeComposer()
+}
+
+// completionHeight is the number of lines the popup takes.
+func (m model) completionHeight() int {
+	if m.completion == nil {
+		return 0
+	}
+	return min(maxCompletions, max(1, len(m.completion.items)))
+}
+
+// fuzzyOrder returns the indexes of the candidates matching query, best
+// first; an empty query keeps every candidate in its original order.
+func fuzzyOrder(query string, candidates []string) []int {
+	if query == "" {
+		order := make([]int, len(candidates))
+		for i := range candidates {
+			order[i] = i
+		}
+		return order
+	}
+	var order []int
+	for _, match := range fuzzy.Find(query, candidates) {
+		order = append(order, match.Index)
+	}
+	return order
+}
+
+func (m *model) loadCompletionSource(repo string, trigger rune) tea.Cmd {
+	if m.completionSources == nil {
+		m.completionSources = make(map[string]string)
+	}
+	m.completionSources[repo+string(trigger)] = "loading"
+	return fetchCompletionSourceCmd(repo, trigger)
+}
+
+func (m *model) handleCompletionSource(msg completionSourceResult) tea.Cmd {
+	if msg.err != nil {
+		// Remember the failure so typing does not retry on every key.
+		m.completionSources[msg.repo+string(msg.trigger)] = "failed"
+		m.status = fmt.Sprintf("Error: suggestions: %s", msg.err.Error())
+		m.statusOverride = true
+		return nil
+	}
+	delete(m.completionSources, msg.repo+string(msg.trigger))
+	if msg.trigger == '#' {
+		if m.repoRefs == nil {
+			m.repoRefs = make(map[string][]refSuggestion)
+		}
+		m.repoRefs[msg.repo] = msg.refs
+	} else {
+		if m.repoAssignees == nil {
+			m.repoAssignees = make(map[string][]string)
+		}
+		m.repoAssignees[msg.repo] = msg.users
+	}
+	if m.pickerMode && m.pickerKind == pickDuplicate && msg.repo == m.actionItem.Repo {
+		m.picker.options = m.duplicateOptions()
+		m.picker.filter()
+	}
+	if m.commentMode && m.completion != nil {
+		return m.updateCompletion()
+	}
+	return nil
+}
+
+// updateCompletionKey handles the keys the popup claims while it shows
+// suggestions. It reports false for keys meant for the textarea.
+func (m *model) updateCompletionKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.completion == nil {
+		return nil, false
+	}
+	if msg.String() == "esc" {
+		m.completionDismissed = m.completion.query + string(m.completion.trigger)
+		m.clearCompletion()
+		return nil, true
+	}
+	if len(m.completion.items) == 0 {
+		return nil, false
+	}
+	switch msg.String() {
+	case "tab":
+		item := m.completion.items[m.completion.index]
+		for range []rune(m.completion.query) {
+			m.textarea, _ = m.textarea.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		}
+		m.textarea.InsertString(item.Insert + " ")
+		m.clearCompletion()
+		return tea.Batch(m.noteDraft(), m.notePreviewEdit()), true
+	case "ctrl+n", "down":
+		m.completion.index = (m.completion.index + 1) % len(m.completion.items)
+		return nil, true
+	case "ctrl+p", "up":
+		m.completion.index = (m.completion.index - 1 + len(m.completion.items)) % len(m.completion.items)
+		return nil, true
+	}
+	return nil, false
+}
+
+// completionView renders the suggestion popup under the composer.
+func (m model) completionView(width int) string {
+	c := m.completion
+	if c == nil {
+		return ""
+	}
+	if len(c.items) == 0 {
+		switch m.completionSources[m.actionItem.Repo+string(c.trigger)] {
+		case "loading":
+			return m.spinner.View() + " " + m.styles.MutedText.Render("Loading suggestions...")
+		case "failed":
+			return m.styles.MutedText.Render("  (suggestions unavailable)")
+		}
+		return m.styles.MutedText.Render("  (no suggestions)")
+	}
+	start := 0
+	if c.index >= maxCompletions {
+		start = c.index - maxCompletions + 1
+	}
+	end := min(len(c.items), start+maxCompletions)
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		item := c.items[i]
+		line := "  " + m.styles.RowTitle.Render(item.Label)
+		if i == c.index {
+			line = m.styles.RowCursor.Render("│ ") + m.styles.RowTitleSelected.Render(item.Label)
+		}
+		if item.Hint != "" {
+			line += "  " + m.styles.MutedText.Render(item.Hint)
+		}
+		lines = append(lines, ansi.Truncate(line, max(10, width), "…"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	authorColumnWidth   = 16
+	ageColumnWidth      = 4
+	commentsColumnWidth = 6
+)
+
+// itemDelegate renders issue rows as two aligned lines and repository group
+// headers as a single rule.
+type itemDelegate struct {
+	styles uiStyles
+	drafts *draftStore
+}
+
+func newItemDelegate(styles uiStyles) itemDelegate {
+	return itemDelegate{styles: styles}
+}
+
+func (d itemDelegate) Height() int                             { return 2 }
+func (d itemDelegate) Spacing() int                            { return 1 }
+func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	switch it := item.(type) {
+	case groupHeader:
+		fmt.Fprint(w, d.renderHeader(it, m.Width(), index == m.Index()))
+	case issueItem:
+		fmt.Fprint(w, d.renderItem(it, m.Width(), index == m.Index()))
+	}
+}
+
+func (d itemDelegate) renderHeader(header groupHeader, width int, selected bool) string {
+	marker := "▾"
+	if header.Collapsed {
+		marker = "▸"
+	}
+	style := d.styles.GroupHeader
+	if selected {
+		style = d.styles.GroupHeaderSelected
+	}
+	title := style.Render(fmt.Sprintf("%s %s", marker, header.Repo))
+	count := d.styles.MutedText.Render(fmt.Sprintf("(%d)", header.Count))
+	line := title + " " + count
+	rule := d.styles.TreeLine.Render(strings.Repeat("─", max(0, width-lipgloss.Width(line)-1)))
+	return line + " " + rule + "\n"
+}
+
+func (d itemDelegate) renderItem(item issueItem, width int, selected bool) string {
+	gutter := "  "
+	titleStyle := d.styles.RowTitle
+	if selected {
+		gutter = d.styles.RowCursor.Render("│ ")
+		titleStyle = d.styles.RowTitleSelected
+	}
+	inner := max(10, width-lipgloss.Width(gutter))
+
+	showAuthor := inner >= 60
+	showComments := inner >= 45
+
+	right := []string{}
+	if showAuthor {
+		right = append(right, padCell(d.styles.RowAuthor.Render(item.Author), authorColumnWidth, false))
+	}
+	right = append(right, padCell(d.styles.MutedText.Render(shortAge(item.Updated)), ageColumnWidth, true))
+	if showComments {
+		comments := ""
+		if item.Comments > 0 {
+			comments = "💬 " + strconv.Itoa(item.Comments)
+		}
+		right = append(right, padCell(d.styles.MutedText.Render(comments), commentsColumnWidth, true))
+	}
+	rightText := strings.Join(right, " ")
+
+	glyph := d.stateGlyph(item)
+	if item.Kind == "PR" {
+		glyph += " " + ciGlyph(item.CI, d.styles)
+	}
+	titleWidth := max(1, inner-lipgloss.Width(glyph)-1-lipgloss.Width(rightText)-1)
+	title := padCell(titleStyle.Render(ansi.Truncate(item.TitleText, titleWidth, "…")), titleWidth, false)
+	first := gutter + glyph + " " + title + " " + rightText
+
+	ref := d.styles.MetaText.Render(fmt.Sprintf("%s #%d", item.Repo, item.Number))
+	chips := renderLabelChips(item.Labels)
+	second := "  " + ref
+	if d.drafts.has(item.Repo, item.Number) {
+		second += " " + d.styles.AccentText.Render("✎ draft")
+	}
+	if chips != "" {
+		second += " " + chips
+	}
+	second = ansi.Truncate(second, inner, "…")
+	if selected {
+		second = d.styles.RowCursor.Render("│ ") + second
+	} else {
+		second = "  " + second
+	}
+	return first + "\n" + second
+}
+
+func (d itemDelegate) stateGlyph(item issueItem) string {
+	switch {
+	case item.Kind == "PR" && item.State == "merged":
+		return d.styles.StateMerged.Render("⬢")
+	case item.Kind == "PR" && item.State == "closed":
+		return d.styles.StateClosed.Render("✖")
+	case item.Kind == "PR" && item.Draft:
+		return d.styles.StateDraft.Render("◇")
+	case item.Kind == "PR":
+		return d.styles.StateOpen.Render("◆")
+	case item.State == "closed":
+		return d.styles.StateMerged.Render("✔")
+	default:
+		return d.styles.StateOpen.Render("●")
+	}
+}
+
+func labelChip(l issueLabel) string {
+	color := strings.TrimPrefix(l.Color, "#")
+	if len(color) != 6 {
+		color = "bcc0cc"
+	}
+	fg := "#4c4f69"
+	if !isLightHex(color) {
+		fg = "#eff1f5"
+	}
+	return lipgloss.NewStyle().
+		Background(lipgloss.Color("#"+color)).
+		Foreground(lipgloss.Color(fg)).
+		Padding(0, 1).
+		Render(l.Name)
+}
+
+func isLightHex(hex string) bool {
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return true
+	}
+	r := float64((v >> 16) & 0xff)
+	g := float64((v >> 8) & 0xff)
+	b := float64(v & 0xff)
+	return 0.299*r+0.587*g+0.114*b > 150
+}
+
+func padCell(text string, width int, alignRight bool) string {
+	text = ansi.Truncate(text, width, "…")
+	pad := width - lipgloss.Width(text)
+	if pad <= 0 {
+		return text
+	}
+	if alignRight {
+		return strings.Repeat(" ", pad) + text
+	}
+	return text + strings.Repeat(" ", pad)
+}
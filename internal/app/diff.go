@@ -0,0 +1,496 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	maxDiffFilePages = 3
+	diffFileListMax  = 40
+	diffGutterWidth  = 5
+	diffTabWidth     = 4
+	maxDiffLines     = 5000
+	diffMarkerWidth  = 2
+)
+
+type diffFile struct {
+	Filename         string
+	PreviousFilename string
+	Status           string
+	Additions        int
+	Deletions        int
+	Patch            string
+}
+
+// fetchPullRequestFiles lists the files changed by a pull request. GitHub
+// omits the patch for binary files and for diffs that are too large.
+func fetchPullRequestFiles(ctx context.Context, token string, item issueItem) ([]diffFile, error) {
+	if item.Repo == "" || item.Number == 0 {
+		return nil, errors.New("missing repo or number")
+	}
+	var files []diffFile
+	for page := 1; page <= maxDiffFilePages; page++ {
+		endpoint, err := url.Parse(fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d/files", item.Repo, item.Number))
+		if err != nil {
+			return nil, err
+		}
+		params := endpoint.Query()
+		params.Set("per_page", "100")
+		params.Set("page", strconv.Itoa(page))
+		endpoint.RawQuery = params.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		addJSONHeaders(req, token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := readAPIError(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var payload []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+			Status           string `json:"status"`
+			Additions        int    `json:"additions"`
+			Deletions        int    `json:"deletions"`
+			Patch            string `json:"patch"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&payload)
+		hasNext := hasLinkRel(resp.Header.Get("Link"), "next")
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range payload {
+			files = append(files, diffFile{
+				Filename:         sanitizeLine(f.Filename),
+				PreviousFilename: sanitizeLine(f.PreviousFilename),
+				Status:           sanitizeLine(f.Status),
+				Additions:        f.Additions,
+				Deletions:        f.Deletions,
+				Patch:            sanitizeText(f.Patch),
+			})
+		}
+		if !hasNext {
+			break
+		}
+	}
+	return files, nil
+}
+
+func (f diffFile) statusGlyph(styles uiStyles) string {
+	switch f.Status {
+	case "added":
+		return styles.DiffAdd.Render("A")
+	case "removed":
+		return styles.DiffDel.Render("D")
+	case "renamed":
+		return styles.DiffHunk.Render("R")
+	default:
+		return styles.CIPending.Render("M")
+	}
+}
+
+// parseHunkHeader reads the starting line numbers from "@@ -a,b +c,d @@".
+func parseHunkHeader(line string) (int, int, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[0] != "@@" {
+		return 0, 0, false
+	}
+	start := func(field, sign string) (int, bool) {
+		if !strings.HasPrefix(field, sign) {
+			return 0, false
+		}
+		num, _, _ := strings.Cut(strings.TrimPrefix(field, sign), ",")
+		n, err := strconv.Atoi(num)
+		return n, err == nil
+	}
+	oldStart, ok := start(fields[1], "-")
+	if !ok {
+		return 0, 0, false
+	}
+	newStart, ok := start(fields[2], "+")
+	if !ok {
+		return 0, 0, false
+	}
+	return oldStart, newStart, true
+}
+
+// diffLine ties a rendered diff row to the file line it shows. Rows that
+// cannot carry a review comment (hunk headers, notes) have Line 0.
+type diffLine struct {
+	Side string
+	Line int
+	Raw  string
+}
+
+// renderDiff draws one file's patch with old/new line numbers and syntax
+// highlighting, one entry per row. It also returns what each row refers to
+// and the row of every hunk header, for hunk navigation.
+func renderDiff(file diffFile, width int, styles uiStyles) ([]string, []diffLine, []int) {
+	if file.Patch == "" {
+		reason := "No patch available: the file is binary or the diff is too large to show."
+		if file.Additions == 0 && file.Deletions == 0 {
+			reason = "No textual changes (mode change, rename or empty file)."
+		}
+		lines := []string{styles.MutedText.Render(reason), styles.MutedText.Render("Press o to open the files view on GitHub.")}
+		return lines, make([]diffLine, len(lines)), nil
+	}
+
+	lang := langFromPath(file.Filename)
+	state := &syntaxState{}
+	gutter := func(n int) string {
+		if n <= 0 {
+			return strings.Repeat(" ", diffGutterWidth)
+		}
+		return fmt.Sprintf("%*d", diffGutterWidth, n)
+	}
+	codeWidth := max(10, width-2*diffGutterWidth-3-diffMarkerWidth)
+
+	var lines []string
+	var refs []diffLine
+	var hunks []int
+	oldLine, newLine := 0, 0
+	patch := strings.Split(strings.TrimRight(file.Patch, "\n"), "\n")
+	truncated := len(patch) > maxDiffLines
+	if truncated {
+		patch = patch[:maxDiffLines]
+	}
+	for _, raw := range patch {
+		raw = strings.ReplaceAll(raw, "\t", strings.Repeat(" ", diffTabWidth))
+		if strings.HasPrefix(raw, "@@") {
+			if o, n, ok := parseHunkHeader(raw); ok {
+				oldLine, newLine = o, n
+			}
+			*state = syntaxState{}
+			hunks = append(hunks, len(lines))
+			lines = append(lines, ansi.Truncate(styles.DiffHunk.Render(raw), max(10, width-diffMarkerWidth), "…"))
+			refs = append(refs, diffLine{})
+			continue
+		}
+		if raw == "" {
+			raw = " "
+		}
+		sign, code := raw[:1], raw[1:]
+		ref := diffLine{Side: "RIGHT", Raw: raw}
+		var oldNum, newNum int
+		signStyle := styles.TreeLine
+		switch sign {
+		case "+":
+			newNum = newLine
+			ref.Line = newLine
+			newLine++
+			signStyle = styles.DiffAdd
+		case "-":
+			oldNum = oldLine
+			ref.Side, ref.Line = "LEFT", oldLine
+			oldLine++
+			signStyle = styles.DiffDel
+		case "\\":
+			lines = append(lines, styles.MutedText.Render(strings.Repeat(" ", 2*diffGutterWidth+1)+raw))
+			refs = append(refs, diffLine{})
+			continue
+		default:
+			oldNum = oldLine
+			newNum = newLine
+			ref.Line = newLine
+			oldLine++
+			newLine++
+		}
+		numbers := styles.MutedText.Render(gutter(oldNum) + gutter(newNum))
+		if sign != " " {
+			numbers = signStyle.Render(gutter(oldNum) + gutter(newNum))
+		}
+		body := ansi.Truncate(highlightLine(code, lang, state, styles), codeWidth, "…")
+		lines = append(lines, numbers+" "+signStyle.Render(sign)+" "+body)
+		refs = append(refs, ref)
+	}
+	if truncated {
+		lines = append(lines, "", styles.MutedText.Render(fmt.Sprintf("Diff truncated after %d lines. Press o to see the rest on GitHub.", maxDiffLines)))
+		refs = append(refs, diffLine{}, diffLine{})
+	}
+	return lines, refs, hunks
+}
+
+func (m model) diffListWidth() int {
+	if m.width < splitMinWidth {
+		return 0
+	}
+	return min(diffFileListMax, m.width/3)
+}
+
+func (m model) diffPaneWidth() int {
+	if w := m.diffListWidth(); w > 0 {
+		return max(listMinimumWidth, m.width-2-w-dividerWidth)
+	}
+	return m.width - 2
+}
+
+// syncDiffViewport renders the selected file into the diff viewport.
+func (m *model) syncDiffViewport() {
+	width := m.diffPaneWidth()
+	m.diffViewport.Width = width
+	m.diffViewport.Height = max(1, m.bodyHeight()-1)
+	if m.diffIndex < 0 || m.diffIndex >= len(m.diffFiles) {
+		m.diffLines, m.diffRefs, m.diffHunks = nil, nil, nil
+		m.diffViewport.SetContent("")
+		return
+	}
+	m.diffLines, m.diffRefs, m.diffHunks = renderDiff(m.diffFiles[m.diffIndex], width, m.styles)
+	m.diffCursor = min(m.diffCursor, max(0, len(m.diffLines)-1))
+	m.refreshDiffContent()
+}
+
+// refreshDiffContent draws the marker column: the cursor, the selected
+// range and rows that already carry a pending comment.
+func (m *model) refreshDiffContent() {
+	start, end := m.diffSelection()
+	commented := make(map[string]bool)
+	if m.diffIndex < len(m.diffFiles) {
+		for _, c := range m.pendingReviews[m.diffFor] {
+			if c.Path == m.diffFiles[m.diffIndex].Filename {
+				commented[fmt.Sprintf("%s:%d", c.Side, c.Line)] = true
+			}
+		}
+	}
+	rows := make([]string, len(m.diffLines))
+	for i, line := range m.diffLines {
+		marker := "  "
+		ref := m.diffRefs[i]
+		switch {
+		case i == m.diffCursor:
+			marker = m.styles.RowCursor.Render("▶ ")
+		case m.diffSelecting && i >= start && i <= end:
+			marker = m.styles.RowCursor.Render("┃ ")
+		case ref.Line > 0 && commented[fmt.Sprintf("%s:%d", ref.Side, ref.Line)]:
+			marker = m.styles.MdMention.Render("● ")
+		}
+		rows[i] = marker + line
+	}
+	m.diffViewport.SetContent(strings.Join(rows, "\n"))
+}
+
+// diffSelection returns the rows covered by the range selection, or just
+// the cursor row when no range is being selected.
+func (m model) diffSelection() (int, int) {
+	if !m.diffSelecting {
+		return m.diffCursor, m.diffCursor
+	}
+	return min(m.diffAnchor, m.diffCursor), max(m.diffAnchor, m.diffCursor)
+}
+
+func (m *model) moveDiffCursor(row int) {
+	if len(m.diffLines) == 0 {
+		return
+	}
+	m.diffCursor = min(max(0, row), len(m.diffLines)-1)
+	if m.diffCursor < m.diffViewport.YOffset {
+		m.diffViewport.SetYOffset(m.diffCursor)
+	} else if m.diffCursor >= m.diffViewport.YOffset+m.diffViewport.Height {
+		m.diffViewport.SetYOffset(m.diffCursor - m.diffViewport.Height + 1)
+	}
+	m.refreshDiffContent()
+}
+
+func (m *model) selectDiffFile(index int) {
+	if index < 0 || index >= len(m.diffFiles) {
+		return
+	}
+	m.diffIndex = index
+	m.diffCursor = 0
+	m.diffSelecting = false
+	m.syncDiffViewport()
+	m.diffViewport.GotoTop()
+}
+
+// stepHunk moves the cursor to the first row of the next or previous hunk
+// and scrolls its header to the top.
+func (m *model) stepHunk(forward bool) {
+	target := -1
+	if forward {
+		for _, h := range m.diffHunks {
+			if h+1 > m.diffCursor {
+				target = h
+				break
+			}
+		}
+	} else {
+		for i := len(m.diffHunks) - 1; i >= 0; i-- {
+			if m.diffHunks[i]+1 < m.diffCursor {
+				target = m.diffHunks[i]
+				break
+			}
+		}
+	}
+	if target < 0 {
+		return
+	}
+	m.diffViewport.SetYOffset(target)
+	m.moveDiffCursor(target + 1)
+}
+
+func (m model) diffFilesURL() string {
+	if m.detailTarget.URL == "" {
+		return ""
+	}
+	return m.detailTarget.URL + "/files"
+}
+
+func (m *model) loadDiff(item issueItem) tea.Cmd {
+	if m.diffFor != item.URL {
+		m.diffFiles = nil
+		m.diffIndex = 0
+		m.diffCursor = 0
+		m.diffSelecting = false
+	}
+	m.diffFor = item.URL
+	m.diffLoading = true
+	m.diffErr = nil
+	return fetchDiffCmd(item)
+}
+
+func (m model) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		if m.diffSelecting {
+			m.diffSelecting = false
+			m.refreshDiffContent()
+			return m, nil
+		}
+		m.diffMode = false
+		return m, nil
+	case "d":
+		m.diffMode = false
+		return m, nil
+	case "n", "tab":
+		m.selectDiffFile(m.diffIndex + 1)
+	case "p", "shift+tab":
+		m.selectDiffFile(m.diffIndex - 1)
+	case "]":
+		m.stepHunk(true)
+	case "[":
+		m.stepHunk(false)
+	case "r":
+		return m, m.loadDiff(m.detailTarget)
+	case "o":
+		return m, openURLCmd(m.diffFilesURL())
+	case "j", "down":
+		m.moveDiffCursor(m.diffCursor + 1)
+	case "k", "up":
+		m.moveDiffCursor(m.diffCursor - 1)
+	case "pgdown", "f", " ":
+		m.moveDiffCursor(m.diffCursor + m.diffViewport.Height)
+	case "pgup", "b":
+		m.moveDiffCursor(m.diffCursor - m.diffViewport.Height)
+	case "ctrl+d":
+		m.moveDiffCursor(m.diffCursor + m.diffViewport.Height/2)
+	case "ctrl+u":
+		m.moveDiffCursor(m.diffCursor - m.diffViewport.Height/2)
+	case "g", "home":
+		m.moveDiffCursor(0)
+	case "G", "end":
+		m.moveDiffCursor(len(m.diffLines) - 1)
+	case "v":
+		m.diffSelecting = !m.diffSelecting
+		m.diffAnchor = m.diffCursor
+		m.refreshDiffContent()
+	case "c":
+		m.startLineComment(false)
+	case "s":
+		m.startLineComment(true)
+	case "P":
+		m.openPendingReview()
+	case "R":
+		m.startReview()
+	}
+	return m, nil
+}
+
+func (m model) diffFileList(width, height int) string {
+	start := 0
+	if m.diffIndex >= height {
+		start = m.diffIndex - height + 1
+	}
+	end := min(len(m.diffFiles), start+height)
+	rows := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		f := m.diffFiles[i]
+		stats := m.styles.DiffAdd.Render(fmt.Sprintf("+%d", f.Additions)) + " " + m.styles.DiffDel.Render(fmt.Sprintf("-%d", f.Deletions))
+		nameWidth := max(4, width-lipgloss.Width(stats)-5)
+		name := f.Filename
+		if lipgloss.Width(name) > nameWidth {
+			name = "…" + ansi.TruncateLeft(name, lipgloss.Width(name)-nameWidth+1, "")
+		}
+		nameStyle := m.styles.RowTitle
+		cursor := "  "
+		if i == m.diffIndex {
+			nameStyle = m.styles.RowTitleSelected
+			cursor = m.styles.RowCursor.Render("│ ")
+		}
+		rows = append(rows, cursor+f.statusGlyph(m.styles)+" "+padCell(nameStyle.Render(name), nameWidth, false)+" "+stats)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m model) diffHeader(width int) string {
+	f := m.diffFiles[m.diffIndex]
+	name := f.Filename
+	if f.PreviousFilename != "" && f.PreviousFilename != f.Filename {
+		name = f.PreviousFilename + " → " + f.Filename
+	}
+	line := fmt.Sprintf("%s %s  %s %s  %s",
+		f.statusGlyph(m.styles),
+		m.styles.AccentText.Render(name),
+		m.styles.DiffAdd.Render(fmt.Sprintf("+%d", f.Additions)),
+		m.styles.DiffDel.Render(fmt.Sprintf("-%d", f.Deletions)),
+		m.styles.MutedText.Render(fmt.Sprintf("file %d/%d • %d hunks", m.diffIndex+1, len(m.diffFiles), len(m.diffHunks))),
+	)
+	if pending := len(m.pendingReviews[m.diffFor]); pending > 0 {
+		line += "  " + m.styles.MdMention.Render(fmt.Sprintf("● %d pending", pending))
+	}
+	return ansi.Truncate(line, width, "…")
+}
+
+func (m model) diffView() string {
+	switch {
+	case m.diffLoading && len(m.diffFiles) == 0:
+		return fmt.Sprintf("%s %s", m.spinner.View(), m.styles.Status.Render("Loading changed files..."))
+	case m.diffErr != nil:
+		return m.styles.StatusErr.Render(fmt.Sprintf("Error loading files: %s", m.diffErr.Error()))
+	case len(m.diffFiles) == 0:
+		return m.styles.MutedText.Render("No changed files.")
+	}
+
+	height := m.bodyHeight()
+	pane := fmt.Sprintf("%s\n%s", m.diffHeader(m.diffPaneWidth()), m.diffViewport.View())
+	listWidth := m.diffListWidth()
+	if listWidth == 0 {
+		return pane
+	}
+	list := lipgloss.NewStyle().Width(listWidth).Height(height).MaxHeight(height).Render(m.diffFileList(listWidth, height))
+	divider := m.styles.TreeLine.Render(strings.TrimSuffix(strings.Repeat(" │ \n", height), "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, divider, pane)
+}
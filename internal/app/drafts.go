@@ -0,0 +1,288 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	draftsFileName = "drafts.json"
+	draftDebounce  = 500 * time.Millisecond
+)
+
+// draft is an unsent comment, kept per item until it is posted or cleared.
+type draft struct {
+	Repo    string    `json:"repo"`
+	Number  int       `json:"number"`
+	Kind    string    `json:"kind"`
+	Title   string    `json:"title"`
+	URL     string    `json:"url"`
+	Body    string    `json:"body"`
+	Updated time.Time `json:"updated"`
+}
+
+// draftStore holds the drafts in memory and knows where to persist them.
+// The model and the list delegate share one store so rows can show a
+// marker for items with a draft.
+type draftStore struct {
+	path    string
+	entries map[string]draft
+}
+
+type draftTickMsg struct {
+	seq int
+}
+
+type draftsSavedResult struct {
+	err error
+}
+
+func draftKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
+
+func draftsFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "github_inbox_tui", draftsFileName), nil
+}
+
+// loadDraftStore reads the saved drafts. A missing or unreadable file
+// yields an empty store; drafts are a convenience, not a reason to fail.
+func loadDraftStore() *draftStore {
+	store := &draftStore{entries: make(map[string]draft)}
+	path, err := draftsFilePath()
+	if err != nil {
+		return store
+	}
+	store.path = path
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return store
+	}
+	var entries []draft
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return store
+	}
+	for _, d := range entries {
+		if strings.TrimSpace(d.Body) != "" {
+			store.entries[draftKey(d.Repo, d.Number)] = d
+		}
+	}
+	return store
+}
+
+func (s *draftStore) get(repo string, number int) (draft, bool) {
+	if s == nil {
+		return draft{}, false
+	}
+	d, ok := s.entries[draftKey(repo, number)]
+	return d, ok
+}
+
+func (s *draftStore) has(repo string, number int) bool {
+	_, ok := s.get(repo, number)
+	return ok
+}
+
+// put stores a draft, or removes it when its body is blank. It reports
+// whether anything changed.
+func (s *draftStore) put(d draft) bool {
+	key := draftKey(d.Repo, d.Number)
+	old, ok := s.entries[key]
+	if strings.TrimSpace(d.Body) == "" {
+		delete(s.entries, key)
+		return ok
+	}
+	if ok && old.Body == d.Body {
+		return false
+	}
+	d.Updated = time.Now()
+	s.entries[key] = d
+	return true
+}
+
+func (s *draftStore) remove(key string) bool {
+	if _, ok := s.entries[key]; !ok {
+		return false
+	}
+	delete(s.entries, key)
+	return true
+}
+
+// sorted returns the drafts, most recently edited first.
+func (s *draftStore) sorted() []draft {
+	drafts := make([]draft, 0, len(s.entries))
+	for _, d := range s.entries {
+		drafts = append(drafts, d)
+	}
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].Updated.After(drafts[j].Updated)
+	})
+	return drafts
+}
+
+// saveCmd writes a snapshot of the store. The snapshot is taken now so the
+// command does not race with later edits.
+func (s *draftStore) saveCmd() tea.Cmd {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := json.MarshalIndent(s.sorted(), "", "  ")
+	path := s.path
+	return func() tea.Msg {
+		if err != nil {
+			return draftsSavedResult{err: err}
+		}
+		return draftsSavedResult{err: writeFileAtomic(path, raw)}
+	}
+}
+
+func writeFileAtomic(path string, raw []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	_, writeErr := tmp.Write(raw)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// draftable reports whether the open composer writes a plain comment,
+// the only kind of text kept as a draft.
+func (m model) draftable() bool {
+	return m.commentMode && (m.composeKind == composeComment || m.composeKind == composeQuoteReply)
+}
+
+// noteDraft records the composer text after an edit and schedules a
+// debounced write to disk.
+func (m *model) noteDraft() tea.Cmd {
+	if !m.draftable() {
+		return nil
+	}
+	changed := m.drafts.put(draft{
+		Repo:   m.actionItem.Repo,
+		Number: m.actionItem.Number,
+		Kind:   m.actionItem.Kind,
+		Title:  m.actionItem.TitleText,
+		URL:    m.actionItem.URL,
+		Body:   m.textarea.Value(),
+	})
+	if !changed {
+		return nil
+	}
+	m.draftSeq++
+	seq := m.draftSeq
+	return tea.Tick(draftDebounce, func(time.Time) tea.Msg {
+		return draftTickMsg{seq: seq}
+	})
+}
+
+func (m *model) handleDraftTick(msg draftTickMsg) tea.Cmd {
+	if msg.seq != m.draftSeq {
+		return nil
+	}
+	return m.drafts.saveCmd()
+}
+
+// restoreDraft fills a freshly opened composer with the item's draft.
+func (m *model) restoreDraft(prefix string) {
+	d, ok := m.drafts.get(m.actionItem.Repo, m.actionItem.Number)
+	if !ok {
+		m.textarea.SetValue(prefix)
+		return
+	}
+	body := d.Body
+	if prefix != "" {
+		body = strings.TrimRight(body, "\n") + "\n\n" + prefix
+	}
+	m.textarea.SetValue(body)
+	m.status = fmt.Sprintf("Restored draft from %s", humanizeSince(d.Updated))
+	m.statusOverride = true
+}
+
+// clearSentDraft drops the draft that was just posted successfully.
+func (m *model) clearSentDraft() tea.Cmd {
+	key := m.sendingDraft
+	m.sendingDraft = ""
+	if key == "" || !m.drafts.remove(key) {
+		return nil
+	}
+	m.refreshList()
+	return m.drafts.saveCmd()
+}
+
+func (m *model) openDraftsPicker() {
+	drafts := m.drafts.sorted()
+	if len(drafts) == 0 {
+		m.status = "No drafts"
+		m.statusOverride = true
+		return
+	}
+	options := make([]pickerOption, 0, len(drafts))
+	for _, d := range drafts {
+		first, _, _ := strings.Cut(strings.TrimSpace(d.Body), "\n")
+		options = append(options, pickerOption{
+			Value: draftKey(d.Repo, d.Number),
+			Label: fmt.Sprintf("%s#%d %s", d.Repo, d.Number, d.Title),
+			Hint:  humanizeSince(d.Updated) + " • " + first,
+		})
+	}
+	m.picker = newPicker(fmt.Sprintf("Drafts (%d)", len(drafts)), options, nil, false)
+	m.pickerKind = pickDrafts
+	m.pickerMode = true
+}
+
+// openDraft reopens the composer on the item a draft belongs to.
+func (m *model) openDraft(key string) {
+	d, ok := m.drafts.entries[key]
+	if !ok {
+		return
+	}
+	m.commentMode = true
+	m.composeKind = composeComment
+	m.actionItem = issueItem{
+		TitleText: d.Title,
+		Repo:      d.Repo,
+		Number:    d.Number,
+		URL:       d.URL,
+		Kind:      d.Kind,
+	}
+	m.textarea.Focus()
+	m.restoreDraft("")
+}
+
+func (m *model) deleteDraft(key string) tea.Cmd {
+	if !m.drafts.remove(key) {
+		return nil
+	}
+	m.picker.remove(key)
+	m.refreshList()
+	m.status = "Draft deleted"
+	m.statusOverride = true
+	if len(m.picker.options) == 0 {
+		m.pickerMode = false
+	}
+	return m.drafts.saveCmd()
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxBodyChars is GitHub's limit for issue, comment and review bodies.
+const maxBodyChars = 65536
+
+type editorResult struct {
+	path string
+	err  error
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, split
+// into program and arguments so values like "code --wait" work.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// openEditor writes the composer text to a temp file and suspends the
+// program while the editor runs on it. The file is reused for the rest of
+// the composition so a failed send can leave it behind for recovery.
+func (m *model) openEditor() tea.Cmd {
+	path := m.editorFile
+	if path == "" {
+		f, err := os.CreateTemp("", "github_inbox_tui-*.md")
+		if err != nil {
+			m.status = fmt.Sprintf("Error: %s", err.Error())
+			m.statusOverride = true
+			return nil
+		}
+		path = f.Name()
+		f.Close()
+		m.editorFile = path
+	}
+	if err := os.WriteFile(path, []byte(m.textarea.Value()), 0o600); err != nil {
+		m.status = fmt.Sprintf("Error: %s", err.Error())
+		m.statusOverride = true
+		return nil
+	}
+
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorResult{path: path, err: err}
+	})
+}
+
+// loadEditorResult puts the edited text back into the composer so it can
+// be reviewed and sent as usual.
+func (m *model) loadEditorResult(msg editorResult) {
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Error: editor: %s", msg.err.Error())
+		m.statusOverride = true
+		return
+	}
+	raw, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.status = fmt.Sprintf("Error: %s", err.Error())
+		m.statusOverride = true
+		return
+	}
+	text := strings.TrimRight(string(raw), "\n")
+	if len([]rune(text)) > maxBodyChars {
+		m.status = fmt.Sprintf("Text is longer than %d characters and was cut; the full text is in %s", maxBodyChars, msg.path)
+	} else {
+		m.status = "Loaded text from editor; ctrl+g to send"
+	}
+	m.statusOverride = true
+	m.textarea.SetValue(text)
+	m.textarea.Focus()
+}
+
+// settleEditorFile removes the editor temp file once what it held was sent,
+// or returns a note pointing at it when sending failed. A kept file is
+// handed off to the user: the next composition gets a fresh one, so
+// neither esc nor ctrl+o there can delete or overwrite it.
+func (m *model) settleEditorFile(err error) string {
+	path := m.editorFile
+	m.editorFile = ""
+	return m.settleFile(path, err)
+}
+
+// settleFile settles an editor file that was already handed off from the
+// composer, such as the one travelling with a pending close.
+func (m *model) settleFile(path string, err error) string {
+	if path == "" {
+		return ""
+	}
+	if err != nil {
+		return fmt.Sprintf(" (text kept in %s)", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.status = fmt.Sprintf("Error: %s", err.Error())
+		m.statusOverride = true
+	}
+	return ""
+}
+
+func (m *model) discardEditorFile() {
+	if m.editorFile == "" {
+		return
+	}
+	if err := os.Remove(m.editorFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.status = fmt.Sprintf("Error: %s", err.Error())
+		m.statusOverride = true
+	}
+	m.editorFile = ""
+}
@@ -101,7 +101,20 @@ func fetchIssues(ctx context.Context, token, query string) ([]issueItem, error)
 			Number        int       `json:"number"`
 			HTMLURL       string    `json:"html_url"`
 			RepositoryURL string    `json:"repository_url"`
-			PullRequest   *struct{} `json:"pull_request"`
+			State         string    `json:"state"`
+			Draft         bool      `json:"draft"`
+			Comments      int       `json:"comments"`
+			UpdatedAt     time.Time `json:"updated_at"`
+			User          struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			Labels []struct {
+				Name  string `json:"name"`
+				Color string `json:"color"`
+			} `json:"labels"`
+			PullRequest *struct {
+				MergedAt *time.Time `json:"merged_at"`
+			} `json:"pull_request"`
 		} `json:"items"`
 	}
 
@@ -113,15 +126,31 @@ func fetchIssues(ctx context.Context, token, query string) ([]issueItem, error)
 	for _, item := range payload.Items {
 		repo := repoNameFromAPIURL(item.RepositoryURL)
 		kind := "Issue"
+		state := item.State
 		if item.PullRequest != nil {
 			kind = "PR"
+			if item.PullRequest.MergedAt != nil {
+				state = "merged"
+			}
+		}
+		labels := make([]issueLabel, 0, len(item.Labels))
+		for _, l := range item.Labels {
+			if l.Name != "" {
+				labels = append(labels, issueLabel{Name: sanitizeLine(l.Name), Color: sanitizeLine(l.Color)})
+			}
 		}
 		items = append(items, issueItem{
-			TitleText: item.Title,
+			TitleText: sanitizeLine(item.Title),
 			Repo:      repo,
 			Number:    item.Number,
-			URL:       item.HTMLURL,
+			URL:       sanitizeLine(item.HTMLURL),
 			Kind:      kind,
+			State:     sanitizeLine(state),
+			Draft:     item.Draft,
+			Author:    sanitizeLine(item.User.Login),
+			Labels:    labels,
+			Comments:  item.Comments,
+			Updated:   item.UpdatedAt,
 		})
 	}
 
@@ -157,7 +186,8 @@ func fetchIssueDetail(ctx context.Context, token string, item issueItem, comment
 		UpdatedAt time.Time `json:"updated_at"`
 		Comments  int       `json:"comments"`
 		Labels    []struct {
-			Name string `json:"name"`
+			Name  string `json:"name"`
+			Color string `json:"color"`
 		} `json:"labels"`
 		Assignees []struct {
 			Login string `json:"login"`
@@ -182,16 +212,16 @@ func fetchIssueDetail(ctx context.Context, token string, item issueItem, comment
 		return detail{}, err
 	}
 
-	labels := make([]string, 0, len(payload.Labels))
+	labels := make([]issueLabel, 0, len(payload.Labels))
 	for _, l := range payload.Labels {
 		if l.Name != "" {
-			labels = append(labels, l.Name)
+			labels = append(labels, issueLabel{Name: sanitizeLine(l.Name), Color: sanitizeLine(l.Color)})
 		}
 	}
 	assignees := make([]string, 0, len(payload.Assignees))
 	for _, a := range payload.Assignees {
 		if a.Login != "" {
-			assignees = append(assignees, a.Login)
+			assignees = append(assignees, sanitizeLine(a.Login))
 		}
 	}
 
@@ -204,13 +234,13 @@ func fetchIssueDetail(ctx context.Context, token string, item issueItem, comment
 	}
 
 	return detail{
-		Title:           payload.Title,
-		Body:            payload.Body,
-		State:           payload.State,
-		Author:          payload.User.Login,
+		Title:           sanitizeLine(payload.Title),
+		Body:            sanitizeText(payload.Body),
+		State:           sanitizeLine(payload.State),
+		Author:          sanitizeLine(payload.User.Login),
 		Updated:         payload.UpdatedAt,
 		Comments:        payload.Comments,
-		URL:             payload.HTMLURL,
+		URL:             sanitizeLine(payload.HTMLURL),
 		Repo:            item.Repo,
 		Number:          item.Number,
 		Kind:            kind,
@@ -218,6 +248,12 @@ func fetchIssueDetail(ctx context.Context, token string, item issueItem, comment
 		Assignees:       assignees,
 		Draft:           prMeta.Draft,
 		Mergeable:       prMeta.Mergeable,
+		MergeableState:  prMeta.MergeableState,
+		NodeID:          prMeta.NodeID,
+		AutoMerge:       prMeta.AutoMerge,
+		HeadSHA:         prMeta.HeadSHA,
+		HeadRef:         prMeta.HeadRef,
+		HeadRepo:        prMeta.HeadRepo,
 		Additions:       prMeta.Additions,
 		Deletions:       prMeta.Deletions,
 		ChangedFiles:    prMeta.ChangedFiles,
@@ -225,6 +261,11 @@ func fetchIssueDetail(ctx context.Context, token string, item issueItem, comment
 		ReviewApprovals: prMeta.ReviewApprovals,
 		ReviewChanges:   prMeta.ReviewChanges,
 		ReviewComments:  prMeta.ReviewComments,
+		Checks:          prMeta.Checks,
+		ChecksError:     prMeta.ChecksError,
+		Threads:         prMeta.Threads,
+		ThreadsError:    prMeta.ThreadsError,
+		Reviewers:       prMeta.Reviewers,
 		CommentList:     comments,
 		CommentPage:     pageInfo.Page,
 		HasNextComments: pageInfo.HasNext,
@@ -242,6 +283,17 @@ type prDetail struct {
 	ReviewApprovals int
 	ReviewChanges   int
 	ReviewComments  int
+	MergeableState  string
+	NodeID          string
+	AutoMerge       *autoMergeState
+	HeadSHA         string
+	HeadRef         string
+	HeadRepo        string
+	Checks          []checkRun
+	ChecksError     string
+	Threads         []reviewThread
+	ThreadsError    string
+	Reviewers       []reviewerState
 }
 
 func fetchPullRequestDetail(ctx context.Context, token string, item issueItem) (prDetail, error) {
@@ -263,12 +315,33 @@ func fetchPullRequestDetail(ctx context.Context, token string, item issueItem) (
 	}
 
 	var payload struct {
-		Draft        bool  `json:"draft"`
-		Mergeable    *bool `json:"mergeable"`
-		Additions    int   `json:"additions"`
-		Deletions    int   `json:"deletions"`
-		ChangedFiles int   `json:"changed_files"`
-		Commits      int   `json:"commits"`
+		Draft          bool   `json:"draft"`
+		Mergeable      *bool  `json:"mergeable"`
+		MergeableState string `json:"mergeable_state"`
+		NodeID         string `json:"node_id"`
+		AutoMerge      *struct {
+			MergeMethod string `json:"merge_method"`
+			EnabledBy   *struct {
+				Login string `json:"login"`
+			} `json:"enabled_by"`
+		} `json:"auto_merge"`
+		Additions          int `json:"additions"`
+		Deletions          int `json:"deletions"`
+		ChangedFiles       int `json:"changed_files"`
+		Commits            int `json:"commits"`
+		RequestedReviewers []struct {
+			Login string `json:"login"`
+		} `json:"requested_reviewers"`
+		RequestedTeams []struct {
+			Slug string `json:"slug"`
+		} `json:"requested_teams"`
+		Head struct {
+			SHA  string `json:"sha"`
+			Ref  string `json:"ref"`
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
@@ -280,6 +353,38 @@ func fetchPullRequestDetail(ctx context.Context, token string, item issueItem) (
 		return prDetail{}, err
 	}
 
+	checks, checksErr := fetchCommitChecks(ctx, token, item.Repo, payload.Head.SHA)
+	checksError := ""
+	if checksErr != nil {
+		checksError = checksErr.Error()
+	}
+
+	threads, threadsErr := fetchReviewThreads(ctx, token, item)
+	threadsError := ""
+	if threadsErr != nil {
+		threadsError = threadsErr.Error()
+	}
+
+	headRepo := ""
+	if payload.Head.Repo != nil {
+		headRepo = sanitizeLine(payload.Head.Repo.FullName)
+	}
+	var requested, teams []string
+	for _, r := range payload.RequestedReviewers {
+		requested = append(requested, sanitizeLine(r.Login))
+	}
+	for _, t := range payload.RequestedTeams {
+		teams = append(teams, sanitizeLine(t.Slug))
+	}
+
+	var autoMerge *autoMergeState
+	if payload.AutoMerge != nil {
+		autoMerge = &autoMergeState{Method: sanitizeLine(payload.AutoMerge.MergeMethod)}
+		if payload.AutoMerge.EnabledBy != nil {
+			autoMerge.EnabledBy = sanitizeLine(payload.AutoMerge.EnabledBy.Login)
+		}
+	}
+
 	return prDetail{
 		Draft:           payload.Draft,
 		Mergeable:       payload.Mergeable,
@@ -290,6 +395,17 @@ func fetchPullRequestDetail(ctx context.Context, token string, item issueItem) (
 		ReviewApprovals: reviews.approvals,
 		ReviewChanges:   reviews.changesRequested,
 		ReviewComments:  reviews.commented,
+		MergeableState:  sanitizeLine(payload.MergeableState),
+		NodeID:          payload.NodeID,
+		AutoMerge:       autoMerge,
+		HeadSHA:         sanitizeLine(payload.Head.SHA),
+		HeadRef:         sanitizeLine(payload.Head.Ref),
+		HeadRepo:        headRepo,
+		Checks:          checks,
+		ChecksError:     checksError,
+		Threads:         threads,
+		ThreadsError:    threadsError,
+		Reviewers:       buildReviewers(requested, teams, reviews.latest),
 	}, nil
 }
 
@@ -297,6 +413,7 @@ type reviewSummary struct {
 	approvals        int
 	changesRequested int
 	commented        int
+	latest           map[string]string
 }
 
 func fetchPullRequestReviews(ctx context.Context, token string, item issueItem) (reviewSummary, error) {
@@ -338,8 +455,9 @@ func fetchPullRequestReviews(ctx context.Context, token string, item issueItem)
 		latestByUser[user] = r.State
 	}
 
-	var summary reviewSummary
-	for _, state := range latestByUser {
+	summary := reviewSummary{latest: make(map[string]string)}
+	for user, state := range latestByUser {
+		summary.latest[sanitizeLine(user)] = strings.ToUpper(state)
 		switch strings.ToUpper(state) {
 		case "APPROVED":
 			summary.approvals++
@@ -383,6 +501,7 @@ func fetchIssueComments(ctx context.Context, token string, item issueItem, page
 	}
 
 	var payload []struct {
+		ID        int64     `json:"id"`
 		Body      string    `json:"body"`
 		UpdatedAt time.Time `json:"updated_at"`
 		User      struct {
@@ -397,8 +516,9 @@ func fetchIssueComments(ctx context.Context, token string, item issueItem, page
 	comments := make([]issueComment, 0, len(payload))
 	for _, c := range payload {
 		comments = append(comments, issueComment{
-			Author:  c.User.Login,
-			Body:    c.Body,
+			ID:      c.ID,
+			Author:  sanitizeLine(c.User.Login),
+			Body:    sanitizeText(c.Body),
 			Updated: c.UpdatedAt,
 		})
 	}
@@ -416,6 +536,7 @@ func repoNameFromAPIURL(apiURL string) string {
 	if apiURL == "" {
 		return "unknown/repo"
 	}
+	apiURL = sanitizeLine(apiURL)
 	parsed, err := url.Parse(apiURL)
 	if err != nil {
 		return "unknown/repo"
@@ -433,9 +554,68 @@ func addJSONHeaders(req *http.Request, token string) {
 	req.Header.Set("Content-Type", "application/json")
 }
 
+// postGraphQL runs a GraphQL query and decodes its data into out. Errors
+// reported in the response body are returned even on a 200 status.
+func postGraphQL(ctx context.Context, token, query string, variables map[string]any, out any) error {
+	data, messages, err := postGraphQLPartial(ctx, token, query, variables)
+	if err != nil {
+		return err
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("github graphql error: %s", strings.Join(messages, "; "))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// postGraphQLPartial runs a GraphQL query and returns its data alongside
+// the error messages in the body, for queries where some fields may fail
+// (say, one repository behind SSO) while the rest are still useful.
+func postGraphQLPartial(ctx context.Context, token, query string, variables map[string]any) (json.RawMessage, []string, error) {
+	raw, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/graphql", strings.NewReader(string(raw)))
+	if err != nil {
+		return nil, nil, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, readAPIError(resp)
+	}
+
+	var payload struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, nil, err
+	}
+	if len(payload.Errors) > 0 {
+		messages := make([]string, 0, len(payload.Errors))
+		for _, e := range payload.Errors {
+			messages = append(messages, sanitizeLine(e.Message))
+		}
+		return payload.Data, messages, nil
+	}
+	return payload.Data, nil, nil
+}
+
 func readAPIError(resp *http.Response) error {
 	body, _ := io.ReadAll(resp.Body)
-	detail := strings.TrimSpace(string(body))
+	detail := strings.TrimSpace(sanitizeText(string(body)))
 	if detail != "" {
 		return fmt.Errorf("github api error: %s: %s", resp.Status, detail)
 	}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type groupHeader struct {
+	Repo      string
+	Count     int
+	Collapsed bool
+}
+
+func (g groupHeader) FilterValue() string { return g.Repo }
+
+func buildListItems(items []issueItem, grouped bool, collapsed map[string]bool) []list.Item {
+	if !grouped {
+		out := make([]list.Item, 0, len(items))
+		for _, item := range items {
+			out = append(out, item)
+		}
+		return out
+	}
+
+	sorted := make([]issueItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Repo) < strings.ToLower(sorted[j].Repo)
+	})
+
+	out := make([]list.Item, 0, len(sorted)+len(sorted)/2)
+	for start := 0; start < len(sorted); {
+		repo := sorted[start].Repo
+		end := start
+		for end < len(sorted) && sorted[end].Repo == repo {
+			end++
+		}
+		header := groupHeader{Repo: repo, Count: end - start, Collapsed: collapsed[repo]}
+		out = append(out, header)
+		if !header.Collapsed {
+			for _, item := range sorted[start:end] {
+				out = append(out, item)
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+func (m *model) refreshList() {
+	selectedURL := ""
+	selectedRepo := ""
+	switch item := m.list.SelectedItem().(type) {
+	case issueItem:
+		selectedURL = item.URL
+		selectedRepo = item.Repo
+	case groupHeader:
+		selectedRepo = item.Repo
+	}
+
+	m.list.SetItems(buildListItems(m.items, m.grouped, m.collapsed))
+	m.restoreSelection(selectedURL, selectedRepo)
+}
+
+func (m *model) restoreSelection(url, repo string) {
+	items := m.list.Items()
+	if len(items) == 0 {
+		return
+	}
+	target := indexOfListItem(items, func(it list.Item) bool {
+		item, ok := it.(issueItem)
+		return ok && url != "" && item.URL == url
+	})
+	if target < 0 {
+		target = indexOfListItem(items, func(it list.Item) bool {
+			header, ok := it.(groupHeader)
+			return ok && repo != "" && header.Repo == repo
+		})
+	}
+	if target < 0 {
+		target = 0
+	}
+	m.list.Select(target)
+	if header, ok := m.list.SelectedItem().(groupHeader); ok && !header.Collapsed {
+		m.moveCursor(1)
+	}
+}
+
+func indexOfListItem(items []list.Item, match func(list.Item) bool) int {
+	for i, it := range items {
+		if match(it) {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveCursor moves the selection by one row in the given direction, stepping
+// over the headers of expanded groups. Collapsed headers stay selectable so
+// they can be expanded again.
+func (m *model) moveCursor(delta int) {
+	start := m.list.Index()
+	for {
+		prev := m.list.Index()
+		if delta > 0 {
+			m.list.CursorDown()
+		} else {
+			m.list.CursorUp()
+		}
+		if m.list.Index() == prev {
+			if header, ok := m.list.SelectedItem().(groupHeader); ok && !header.Collapsed {
+				m.list.Select(start)
+			}
+			return
+		}
+		header, ok := m.list.SelectedItem().(groupHeader)
+		if !ok || header.Collapsed {
+			return
+		}
+	}
+}
+
+// skipGroupHeader moves off an expanded group header that a jump (page,
+// home, end) landed on, preferring the direction of the jump.
+func (m *model) skipGroupHeader(delta int) {
+	if header, ok := m.list.SelectedItem().(groupHeader); !ok || header.Collapsed {
+		return
+	}
+	m.moveCursor(delta)
+	if header, ok := m.list.SelectedItem().(groupHeader); ok && !header.Collapsed {
+		m.moveCursor(-delta)
+	}
+}
+
+func (m *model) toggleGroup() {
+	repo := ""
+	switch item := m.list.SelectedItem().(type) {
+	case issueItem:
+		repo = item.Repo
+	case groupHeader:
+		repo = item.Repo
+	}
+	if repo == "" {
+		return
+	}
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[repo] = !m.collapsed[repo]
+	m.list.SetItems(buildListItems(m.items, m.grouped, m.collapsed))
+	m.restoreSelection("", repo)
+}
+
+func (m *model) toggleAllGroups() {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	collapse := false
+	for _, item := range m.items {
+		if !m.collapsed[item.Repo] {
+			collapse = true
+			break
+		}
+	}
+	for _, item := range m.items {
+		m.collapsed[item.Repo] = collapse
+	}
+	m.refreshList()
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxLabelPages = 5
+
+func fetchRepoLabels(ctx context.Context, token, repo string) ([]issueLabel, error) {
+	var labels []issueLabel
+	for page := 1; page <= maxLabelPages; page++ {
+		endpoint, err := url.Parse(fmt.Sprintf("https://api.github.com/repos/%s/labels", repo))
+		if err != nil {
+			return nil, err
+		}
+		params := endpoint.Query()
+		params.Set("per_page", "100")
+		params.Set("page", strconv.Itoa(page))
+		endpoint.RawQuery = params.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		addJSONHeaders(req, token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := readAPIError(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var payload []struct {
+			Name        string `json:"name"`
+			Color       string `json:"color"`
+			Description string `json:"description"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&payload)
+		hasNext := hasLinkRel(resp.Header.Get("Link"), "next")
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range payload {
+			labels = append(labels, issueLabel{
+				Name:        sanitizeLine(l.Name),
+				Color:       sanitizeLine(l.Color),
+				Description: sanitizeLine(l.Description),
+			})
+		}
+		if !hasNext {
+			break
+		}
+	}
+	return labels, nil
+}
+
+// setIssueLabels replaces the labels of an issue or pull request and
+// returns the labels GitHub reports afterwards.
+func setIssueLabels(ctx context.Context, token string, item issueItem, names []string) ([]issueLabel, error) {
+	if item.Repo == "" || item.Number == 0 {
+		return nil, errors.New("missing repo or number")
+	}
+	raw, err := json.Marshal(map[string][]string{"labels": names})
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/labels", item.Repo, item.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return nil, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, readAPIError(resp)
+	}
+
+	var payload []struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	labels := make([]issueLabel, 0, len(payload))
+	for _, l := range payload {
+		labels = append(labels, issueLabel{Name: sanitizeLine(l.Name), Color: sanitizeLine(l.Color)})
+	}
+	return labels, nil
+}
+
+// actionTarget is the item an action applies to: the open detail item, or
+// the selected list row.
+func (m model) actionTarget() (issueItem, bool) {
+	if m.showDetail {
+		if m.detailItem.Title == "" || m.detailItem.URL != m.detailTarget.URL {
+			return issueItem{}, false
+		}
+		return m.detailTarget, true
+	}
+	item, ok := m.list.SelectedItem().(issueItem)
+	return item, ok
+}
+
+// currentLabels returns the freshest known labels of an item: the detail
+// view's when it shows the item, otherwise the list row's.
+func (m model) currentLabels(item issueItem) []issueLabel {
+	if m.detailItem.URL == item.URL && m.detailItem.Title != "" {
+		return m.detailItem.Labels
+	}
+	for _, it := range m.items {
+		if it.URL == item.URL {
+			return it.Labels
+		}
+	}
+	return item.Labels
+}
+
+func (m *model) startLabelPicker() tea.Cmd {
+	target, ok := m.actionTarget()
+	if !ok {
+		return nil
+	}
+	m.pickerTarget = target
+	if labels, ok := m.repoLabels[target.Repo]; ok {
+		m.openLabelPicker(labels)
+		return nil
+	}
+	m.pickerLoading = pickLabels
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Loading labels for %s...", target.Repo)
+	m.statusOverride = true
+	return fetchLabelsCmd(target.Repo)
+}
+
+func (m *model) openLabelPicker(labels []issueLabel) {
+	options := make([]pickerOption, 0, len(labels))
+	for _, l := range labels {
+		options = append(options, pickerOption{Value: l.Name, Label: l.Name, Color: l.Color, Hint: l.Description})
+	}
+	current := m.currentLabels(m.pickerTarget)
+	checked := make([]string, 0, len(current))
+	for _, l := range current {
+		checked = append(checked, l.Name)
+	}
+	m.picker = newPicker(fmt.Sprintf("Labels • %s#%d", m.pickerTarget.Repo, m.pickerTarget.Number), options, checked, true)
+	m.pickerKind = pickLabels
+	m.pickerMode = true
+}
+
+// applyLabels updates the list row and detail view right away and sends
+// the change; labelsResult reverts it if GitHub refuses.
+func (m *model) applyLabels(names []string) tea.Cmd {
+	colors := make(map[string]string)
+	for _, l := range m.repoLabels[m.pickerTarget.Repo] {
+		colors[l.Name] = l.Color
+	}
+	labels := make([]issueLabel, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, issueLabel{Name: name, Color: colors[name]})
+	}
+	previous := append([]issueLabel(nil), m.currentLabels(m.pickerTarget)...)
+	m.setItemLabels(m.pickerTarget.URL, labels)
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Updating labels on %s#%d...", m.pickerTarget.Repo, m.pickerTarget.Number)
+	m.statusOverride = true
+	return setLabelsCmd(m.pickerTarget, names, previous)
+}
+
+func (m *model) setItemLabels(itemURL string, labels []issueLabel) {
+	for i := range m.items {
+		if m.items[i].URL == itemURL {
+			m.items[i].Labels = labels
+		}
+	}
+	m.refreshList()
+	if m.detailItem.URL == itemURL {
+		m.detailItem.Labels = labels
+		m.syncDetailViewport()
+	}
+}
+
+func renderLabelChips(labels []issueLabel) string {
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, labelChip(l))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	layoutSplit = iota
+	layoutList
+)
+
+const (
+	splitMinWidth    = 100
+	splitDefault     = 0.45
+	splitMinRatio    = 0.25
+	splitMaxRatio    = 0.75
+	splitStep        = 0.05
+	previewDebounce  = 250 * time.Millisecond
+	chromeHeight     = 7
+	dividerWidth     = 3
+	listMinimumWidth = 20
+)
+
+type previewTickMsg struct {
+	seq int
+}
+
+// splitActive reports whether the list and the detail preview share the
+// screen. Narrow terminals always fall back to the list alone.
+func (m model) splitActive() bool {
+	return !m.showDetail && m.layout == layoutSplit && m.width >= splitMinWidth
+}
+
+func (m model) bodyHeight() int {
+	return max(1, m.height-chromeHeight)
+}
+
+func (m model) listPaneWidth() int {
+	if !m.splitActive() {
+		return m.width - 2
+	}
+	return max(listMinimumWidth, int(float64(m.width)*m.splitRatio))
+}
+
+func (m model) previewPaneWidth() int {
+	return max(listMinimumWidth, m.width-m.listPaneWidth()-dividerWidth)
+}
+
+func (m model) detailPaneWidth() int {
+	if m.splitActive() {
+		return m.previewPaneWidth()
+	}
+	return m.width - 2
+}
+
+func (m *model) resize() {
+	m.list.SetSize(m.listPaneWidth(), m.bodyHeight())
+	m.syncDetailViewport()
+	if m.diffMode {
+		m.syncDiffViewport()
+	}
+}
+
+// syncDetailViewport re-renders the scrollable part of the detail view for
+// the current pane size. The scroll offset is kept, so refreshing the same
+// item does not jump back to the top.
+func (m *model) syncDetailViewport() {
+	width := m.detailPaneWidth()
+	header := m.detailHeader(width)
+	m.viewport.Width = width
+	m.viewport.Height = max(1, m.bodyHeight()-lipgloss.Height(header)-1)
+	m.viewport.SetContent(m.detailBody(width))
+}
+
+// revealLine scrolls the detail viewport so that line is visible.
+func (m *model) revealLine(line int) {
+	if line < 0 {
+		return
+	}
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line)
+	}
+}
+
+func (m *model) scrollDetail(key string) {
+	switch key {
+	case "j", "down":
+		m.viewport.ScrollDown(1)
+	case "k", "up":
+		m.viewport.ScrollUp(1)
+	case "pgdown":
+		m.viewport.PageDown()
+	case "pgup":
+		m.viewport.PageUp()
+	case "ctrl+d":
+		m.viewport.HalfPageDown()
+	case "ctrl+u":
+		m.viewport.HalfPageUp()
+	case "g", "home":
+		m.viewport.GotoTop()
+	case "G", "end":
+		m.viewport.GotoBottom()
+	}
+}
+
+func (m model) scrollLine(width int) string {
+	percent := fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100)
+	return padCell(m.styles.MutedText.Render(percent), width, true)
+}
+
+// cycleLayout steps through split, list-only and detail-only views.
+func (m *model) cycleLayout() tea.Cmd {
+	switch {
+	case m.showDetail:
+		m.showDetail = false
+		m.layout = layoutSplit
+		m.resize()
+		return m.schedulePreview()
+	case m.layout == layoutSplit:
+		m.layout = layoutList
+		m.resize()
+		return nil
+	default:
+		m.layout = layoutSplit
+		item, ok := m.list.SelectedItem().(issueItem)
+		if !ok {
+			m.resize()
+			return nil
+		}
+		m.showDetail = true
+		m.resize()
+		if m.detailTarget.URL == item.URL && m.detailItem.Title != "" {
+			return nil
+		}
+		return m.loadDetail(item, 1)
+	}
+}
+
+func (m *model) resizeSplit(delta float64) {
+	ratio := m.splitRatio + delta
+	if ratio < splitMinRatio {
+		ratio = splitMinRatio
+	}
+	if ratio > splitMaxRatio {
+		ratio = splitMaxRatio
+	}
+	m.splitRatio = ratio
+	m.resize()
+}
+
+func (m *model) loadDetail(item issueItem, page int) tea.Cmd {
+	m.detailTarget = item
+	m.commentPage = page
+	m.detailLoading = true
+	m.detailErr = nil
+	if m.detailSection == sectionTimeline && m.timelineFor != item.URL {
+		return tea.Batch(fetchDetailCmd(item, page), m.loadTimeline(item, 1))
+	}
+	return fetchDetailCmd(item, page)
+}
+
+func (m *model) loadTimeline(item issueItem, page int) tea.Cmd {
+	if m.timelineFor != item.URL {
+		// Another item's events must not show under this item's header
+		// while its own timeline loads.
+		m.timeline = nil
+		m.timelinePage = commentPageInfo{}
+	}
+	m.timelineFor = item.URL
+	m.timelineLoading = true
+	m.timelineErr = nil
+	return fetchTimelineCmd(item, page)
+}
+
+// schedulePreview debounces preview loads while the cursor is moving: only
+// the tick carrying the latest sequence number triggers a fetch.
+func (m *model) schedulePreview() tea.Cmd {
+	if !m.splitActive() {
+		return nil
+	}
+	m.previewSeq++
+	seq := m.previewSeq
+	return tea.Tick(previewDebounce, func(time.Time) tea.Msg {
+		return previewTickMsg{seq: seq}
+	})
+}
+
+func (m *model) handlePreviewTick(msg previewTickMsg) tea.Cmd {
+	if msg.seq != m.previewSeq || !m.splitActive() {
+		return nil
+	}
+	item, ok := m.list.SelectedItem().(issueItem)
+	if !ok || item.URL == m.detailTarget.URL {
+		return nil
+	}
+	return m.loadDetail(item, 1)
+}
+
+func (m model) splitView(list string) string {
+	height := m.bodyHeight()
+	listPane := lipgloss.NewStyle().Width(m.listPaneWidth()).Height(height).MaxHeight(height).Render(list)
+
+	preview := m.styles.MutedText.Render("Select an item to preview it.")
+	if _, ok := m.list.SelectedItem().(issueItem); ok && (m.detailTarget.URL != "" || m.detailLoading) {
+		preview = m.detailView(m.previewPaneWidth())
+	}
+	previewPane := lipgloss.NewStyle().Width(m.previewPaneWidth()).Height(height).MaxHeight(height).Render(preview)
+
+	divider := m.styles.TreeLine.Render(strings.TrimSuffix(strings.Repeat(" │ \n", height), "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, listPane, divider, previewPane)
+}
@@ -0,0 +1,630 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	headingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	ruleRe        = regexp.MustCompile(`^([-*_])(\s*[-*_]){2,}$`)
+	listItemRe    = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	taskRe        = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	tableDelimRe  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBreakRe   = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlImageRe   = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	htmlAltRe     = regexp.MustCompile(`(?i)\balt="([^"]*)"`)
+	htmlTagRe     = regexp.MustCompile(`(?i)</?(details|summary|p|div|span|sub|sup|kbd|b|i|em|strong|picture|source|center|a)\b[^>]*>`)
+)
+
+type mdRenderer struct {
+	styles uiStyles
+}
+
+// renderMarkdown renders GitHub flavored markdown as styled terminal text
+// wrapped to width.
+func renderMarkdown(src string, width int, styles uiStyles) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	r := mdRenderer{styles: styles}
+	return strings.Join(r.blocks(strings.Split(src, "\n"), max(10, width)), "\n")
+}
+
+func (r mdRenderer) blocks(lines []string, width int) []string {
+	var out []string
+	emit := func(block []string) {
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, block...)
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case strings.HasPrefix(trimmed, "<!--") && !strings.Contains(trimmed, "-->"):
+			for i < len(lines) && !strings.Contains(lines[i], "-->") {
+				i++
+			}
+			i++
+		case isFence(trimmed):
+			fence := trimmed[:3]
+			lang := strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++
+			emit(r.codeBlock(code, lang, width))
+		case headingRe.MatchString(trimmed):
+			match := headingRe.FindStringSubmatch(trimmed)
+			emit(r.heading(len(match[1]), match[2], width))
+			i++
+		case ruleRe.MatchString(trimmed):
+			emit([]string{r.styles.TreeLine.Render(strings.Repeat("─", width))})
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				text := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(text, " "))
+				i++
+			}
+			emit(r.quote(quoted, width))
+		case isTableStart(lines, i):
+			var rows []string
+			for i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != "" {
+				rows = append(rows, lines[i])
+				i++
+			}
+			emit(r.table(rows, width))
+		case listItemRe.MatchString(line):
+			var items []string
+			for i < len(lines) {
+				current := lines[i]
+				if strings.TrimSpace(current) == "" {
+					if i+1 < len(lines) && (listItemRe.MatchString(lines[i+1]) || strings.HasPrefix(lines[i+1], "  ")) {
+						i++
+						continue
+					}
+					break
+				}
+				if !listItemRe.MatchString(current) && !strings.HasPrefix(current, " ") && len(items) > 0 && startsBlock(lines, i) {
+					break
+				}
+				items = append(items, current)
+				i++
+			}
+			emit(r.list(items, width))
+		default:
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				if len(para) > 0 && startsBlock(lines, i) {
+					break
+				}
+				para = append(para, strings.TrimSpace(lines[i]))
+				i++
+			}
+			emit(r.paragraph(para, width))
+		}
+	}
+	return out
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// isTableStart reports a header row followed by a delimiter row with the
+// same number of columns. The delimiter must contain a pipe, so a setext
+// underline or horizontal rule below a line with a pipe stays what it is.
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") {
+		return false
+	}
+	delim := lines[i+1]
+	if !strings.Contains(delim, "|") || !strings.Contains(delim, "-") || !tableDelimRe.MatchString(delim) {
+		return false
+	}
+	return len(splitTableRow(delim)) == len(splitTableRow(lines[i]))
+}
+
+func startsBlock(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	return isFence(trimmed) ||
+		headingRe.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">") ||
+		listItemRe.MatchString(lines[i]) ||
+		isTableStart(lines, i)
+}
+
+func (r mdRenderer) paragraph(lines []string, width int) []string {
+	rendered := make([]string, 0, len(lines))
+	for _, line := range lines {
+		rendered = append(rendered, r.inline(line, r.styles.BodyText))
+	}
+	return strings.Split(ansi.Wrap(strings.Join(rendered, "\n"), width, ""), "\n")
+}
+
+func (r mdRenderer) heading(level int, text string, width int) []string {
+	style := r.styles.MdHeading
+	if level > 2 {
+		style = r.styles.MdSubheading
+	}
+	prefix := ""
+	if level > 1 {
+		prefix = style.Render(strings.Repeat("#", level)) + " "
+	}
+	out := strings.Split(ansi.Wrap(prefix+r.inline(text, style), width, ""), "\n")
+	if level == 1 {
+		underline := min(width, max(3, lipgloss.Width(out[len(out)-1])))
+		out = append(out, r.styles.MdHeading.Render(strings.Repeat("═", underline)))
+	}
+	return out
+}
+
+func (r mdRenderer) quote(lines []string, width int) []string {
+	inner := r.blocks(lines, max(10, width-2))
+	bar := r.styles.MdQuoteBar.Render("▌ ")
+	out := make([]string, 0, len(inner))
+	for _, line := range inner {
+		out = append(out, bar+r.styles.MdQuote.Render(line))
+	}
+	return out
+}
+
+func (r mdRenderer) codeBlock(code []string, lang string, width int) []string {
+	bar := r.styles.TreeLine.Render("│ ")
+	out := make([]string, 0, len(code)+1)
+	if lang != "" {
+		out = append(out, r.styles.MutedText.Render(lang))
+	}
+	state := syntaxState{}
+	for _, line := range code {
+		highlighted := highlightLine(line, lang, &state, r.styles)
+		for _, part := range strings.Split(ansi.Hardwrap(highlighted, max(1, width-2), true), "\n") {
+			out = append(out, bar+part)
+		}
+	}
+	if len(code) == 0 {
+		out = append(out, bar)
+	}
+	return out
+}
+
+type mdListItem struct {
+	indent int
+	marker string
+	text   string
+}
+
+func (r mdRenderer) list(lines []string, width int) []string {
+	var items []mdListItem
+	for _, line := range lines {
+		if match := listItemRe.FindStringSubmatch(line); match != nil {
+			items = append(items, mdListItem{indent: len(match[1]), marker: match[2], text: match[3]})
+			continue
+		}
+		if len(items) > 0 {
+			items[len(items)-1].text += "\n" + strings.TrimSpace(line)
+		}
+	}
+
+	var out []string
+	var indents []int
+	for _, item := range items {
+		for len(indents) > 0 && item.indent < indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || item.indent > indents[len(indents)-1] {
+			indents = append(indents, item.indent)
+		}
+		depth := len(indents) - 1
+
+		bullet := r.styles.MdBullet.Render([]string{"•", "◦", "▪"}[depth%3])
+		if strings.IndexAny(item.marker, ".)") >= 0 {
+			bullet = r.styles.MdBullet.Render(item.marker)
+		}
+		text := item.text
+		if task := taskRe.FindStringSubmatch(text); task != nil {
+			if task[1] == " " {
+				bullet = r.styles.MutedText.Render("☐")
+			} else {
+				bullet = r.styles.StateOpen.Render("☑")
+			}
+			text = task[2]
+		}
+
+		lead := strings.Repeat("  ", depth) + bullet + " "
+		hang := strings.Repeat(" ", lipgloss.Width(lead))
+		rendered := make([]string, 0, 1)
+		for _, part := range strings.Split(text, "\n") {
+			rendered = append(rendered, r.inline(part, r.styles.BodyText))
+		}
+		wrapped := strings.Split(ansi.Wrap(strings.Join(rendered, "\n"), max(5, width-lipgloss.Width(lead)), ""), "\n")
+		for n, line := range wrapped {
+			if n == 0 {
+				out = append(out, lead+line)
+			} else {
+				out = append(out, hang+line)
+			}
+		}
+	}
+	return out
+}
+
+func (r mdRenderer) table(rows []string, width int) []string {
+	if len(rows) < 2 {
+		return r.paragraph(rows, width)
+	}
+	header := splitTableRow(rows[0])
+	aligns := make([]string, len(header))
+	for n, cell := range splitTableRow(rows[1]) {
+		if n >= len(aligns) {
+			break
+		}
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns[n] = "center"
+		case right:
+			aligns[n] = "right"
+		}
+	}
+
+	cells := make([][]string, 0, len(rows)-1)
+	for n, row := range rows {
+		if n == 1 {
+			continue
+		}
+		style := r.styles.BodyText
+		if n == 0 {
+			style = r.styles.MdTableHeader
+		}
+		parts := splitTableRow(row)
+		rendered := make([]string, len(header))
+		for c := range header {
+			if c < len(parts) {
+				rendered[c] = r.inline(parts[c], style)
+			}
+		}
+		cells = append(cells, rendered)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range cells {
+		for c, cell := range row {
+			widths[c] = max(widths[c], lipgloss.Width(cell))
+		}
+	}
+	sep := r.styles.TreeLine.Render(" │ ")
+	total := func() int {
+		sum := 3 * (len(widths) - 1)
+		for _, w := range widths {
+			sum += w
+		}
+		return sum
+	}
+	for total() > width {
+		widest := 0
+		for c := range widths {
+			if widths[c] > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	out := make([]string, 0, len(cells)+1)
+	for n, row := range cells {
+		parts := make([]string, len(row))
+		for c, cell := range row {
+			parts[c] = alignCell(cell, widths[c], aligns[c])
+		}
+		out = append(out, strings.Join(parts, sep))
+		if n == 0 {
+			rules := make([]string, len(widths))
+			for c, w := range widths {
+				rules[c] = strings.Repeat("─", w)
+			}
+			out = append(out, r.styles.TreeLine.Render(strings.Join(rules, "─┼─")))
+		}
+	}
+	return out
+}
+
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		if row[i] == '\\' && i+1 < len(row) && row[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if row[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(row[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func alignCell(cell string, width int, align string) string {
+	cell = ansi.Truncate(cell, width, "…")
+	pad := width - lipgloss.Width(cell)
+	switch align {
+	case "right":
+		return strings.Repeat(" ", pad) + cell
+	case "center":
+		return strings.Repeat(" ", pad/2) + cell + strings.Repeat(" ", pad-pad/2)
+	default:
+		return cell + strings.Repeat(" ", pad)
+	}
+}
+
+const (
+	spanBold = 1 << iota
+	spanItalic
+	spanStrike
+	spanCode
+	spanLink
+	spanURL
+	spanMention
+)
+
+type mdSpan struct {
+	text  string
+	flags int
+}
+
+func (r mdRenderer) inline(text string, base lipgloss.Style) string {
+	text = htmlCommentRe.ReplaceAllString(text, "")
+	text = htmlBreakRe.ReplaceAllString(text, " ")
+	text = htmlImageRe.ReplaceAllStringFunc(text, func(tag string) string {
+		alt := "image"
+		if match := htmlAltRe.FindStringSubmatch(tag); match != nil && match[1] != "" {
+			alt = match[1]
+		}
+		return "[image: " + alt + "]"
+	})
+	text = htmlTagRe.ReplaceAllString(text, "")
+
+	var out strings.Builder
+	for _, span := range parseInline(text, 0) {
+		out.WriteString(r.spanStyle(span.flags, base).Render(span.text))
+	}
+	return out.String()
+}
+
+func (r mdRenderer) spanStyle(flags int, base lipgloss.Style) lipgloss.Style {
+	style := base
+	switch {
+	case flags&spanCode != 0:
+		style = r.styles.MdCode
+	case flags&spanURL != 0:
+		style = r.styles.MutedText
+	case flags&spanLink != 0:
+		style = r.styles.MdLink
+	case flags&spanMention != 0:
+		style = r.styles.MdMention
+	}
+	if flags&spanBold != 0 {
+		style = style.Bold(true)
+	}
+	if flags&spanItalic != 0 {
+		style = style.Italic(true)
+	}
+	if flags&spanStrike != 0 {
+		style = style.Strikethrough(true)
+	}
+	return style
+}
+
+// parseInline splits a line into runs of text that share the same inline
+// formatting. Unmatched delimiters are kept as literal text.
+func parseInline(text string, flags int) []mdSpan {
+	var spans []mdSpan
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			spans = append(spans, mdSpan{text: plain.String(), flags: flags})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+		atWordStart := i == 0 || !isWordByte(text[i-1])
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!|~<>", text[i+1]) >= 0:
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+		case c == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:ticks]
+			if end := strings.Index(rest[ticks:], fence); end >= 0 {
+				flush()
+				code := rest[ticks : ticks+end]
+				if strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") && len(strings.TrimSpace(code)) > 0 {
+					code = code[1 : len(code)-1]
+				}
+				spans = append(spans, mdSpan{text: code, flags: flags | spanCode})
+				i += ticks + end + ticks
+				continue
+			}
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if label, target, n, ok := parseLink(rest[1:]); ok {
+				flush()
+				if label == "" {
+					label = "image"
+				}
+				spans = append(spans, mdSpan{text: "[image: " + label + "]", flags: flags | spanLink})
+				if target != "" {
+					spans = append(spans, mdSpan{text: " " + target, flags: flags | spanURL})
+				}
+				i += 1 + n
+				continue
+			}
+		case c == '[':
+			if label, target, n, ok := parseLink(rest); ok {
+				flush()
+				spans = append(spans, parseInline(label, flags|spanLink)...)
+				if target != "" && target != label {
+					spans = append(spans, mdSpan{text: " (" + target + ")", flags: flags | spanURL})
+				}
+				i += n
+				continue
+			}
+		case c == '<' && (strings.HasPrefix(rest, "<http://") || strings.HasPrefix(rest, "<https://")):
+			if end := strings.IndexByte(rest, '>'); end > 0 {
+				flush()
+				spans = append(spans, mdSpan{text: rest[1:end], flags: flags | spanLink})
+				i += end + 1
+				continue
+			}
+		case atWordStart && (strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://")):
+			end := strings.IndexAny(rest, " \t<>")
+			if end < 0 {
+				end = len(rest)
+			}
+			for end > 0 && strings.IndexByte(".,;:!?)'\"", rest[end-1]) >= 0 {
+				end--
+			}
+			flush()
+			spans = append(spans, mdSpan{text: rest[:end], flags: flags | spanLink})
+			i += end
+			continue
+		case atWordStart && (c == '@' || c == '#') && i+1 < len(text):
+			end := 1
+			for end < len(rest) && (isWordByte(rest[end]) || rest[end] == '-' || (c == '@' && rest[end] == '/')) {
+				if c == '#' && (rest[end] < '0' || rest[end] > '9') {
+					break
+				}
+				end++
+			}
+			if end > 1 {
+				flush()
+				spans = append(spans, mdSpan{text: rest[:end], flags: flags | spanMention})
+				i += end
+				continue
+			}
+		case c == '~' && strings.HasPrefix(rest, "~~"):
+			if end := strings.Index(rest[2:], "~~"); end > 0 {
+				flush()
+				spans = append(spans, parseInline(rest[2:2+end], flags|spanStrike)...)
+				i += end + 4
+				continue
+			}
+		case c == '*' || (c == '_' && atWordStart):
+			run := len(rest) - len(strings.TrimLeft(rest, string(c)))
+			run = min(run, 3)
+			delim := rest[:run]
+			if run < len(rest) && rest[run] != ' ' {
+				if end := closingDelimiter(rest[run:], delim); end > 0 {
+					extra := 0
+					switch run {
+					case 1:
+						extra = spanItalic
+					case 2:
+						extra = spanBold
+					default:
+						extra = spanBold | spanItalic
+					}
+					flush()
+					spans = append(spans, parseInline(rest[run:run+end], flags|extra)...)
+					i += run + end + run
+					continue
+				}
+			}
+		}
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+	return spans
+}
+
+// closingDelimiter finds the delimiter that closes an emphasis run, skipping
+// ones preceded by whitespace and, for underscores, ones inside words.
+func closingDelimiter(text, delim string) int {
+	for start := 0; start < len(text); {
+		idx := strings.Index(text[start:], delim)
+		if idx < 0 {
+			return -1
+		}
+		pos := start + idx
+		after := pos + len(delim)
+		validBefore := pos > 0 && text[pos-1] != ' '
+		validAfter := after >= len(text) || text[after] != delim[0]
+		if delim[0] == '_' && after < len(text) && isWordByte(text[after]) {
+			validAfter = false
+		}
+		if validBefore && validAfter {
+			return pos
+		}
+		start = pos + 1
+	}
+	return -1
+}
+
+// parseLink parses "[label](target)" at the start of text and returns the
+// number of bytes consumed.
+func parseLink(text string) (string, string, int, bool) {
+	if !strings.HasPrefix(text, "[") {
+		return "", "", 0, false
+	}
+	depth := 0
+	closeLabel := -1
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeLabel = i
+			}
+		}
+		if closeLabel >= 0 {
+			break
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(text) || text[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(text[closeLabel+1:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	target := strings.TrimSpace(text[closeLabel+2 : closeLabel+1+end])
+	if space := strings.IndexByte(target, ' '); space > 0 {
+		target = target[:space]
+	}
+	return text[1:closeLabel], strings.Trim(target, "<>"), closeLabel + 2 + end, true
+}
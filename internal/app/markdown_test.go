@@ -0,0 +1,67 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestIsTableStart(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{"table", "a | b\n--- | ---", true},
+		{"outer pipes", "| a | b |\n|:-|-:|", true},
+		{"single column", "| a |\n| --- |", true},
+		{"setext underline", "a | b\n---", false},
+		{"single dash", "x|y\n-", false},
+		{"centered rule", "a|b\n:-:", false},
+		{"column mismatch", "a | b | c\n--- | ---", false},
+		{"no pipe in header", "a b\n--- | ---", false},
+		{"last line", "a | b", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTableStart(strings.Split(tc.src, "\n"), 0); got != tc.want {
+				t.Errorf("isTableStart(%q) = %v, want %v", tc.src, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownPipeBeforeRule(t *testing.T) {
+	styles := newStyles()
+	for _, src := range []string{
+		"a | b\n---",
+		"x|y\n-",
+		"a|b\n:-:",
+		"a | b\n---\nmore | text",
+		"| a |\n| --- | --- |",
+	} {
+		t.Run(src, func(t *testing.T) {
+			out := ansi.Strip(renderMarkdown(src, 60, styles))
+			if !strings.Contains(out, "a") && !strings.Contains(out, "x") {
+				t.Errorf("renderMarkdown(%q) lost the text: %q", src, out)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownTable(t *testing.T) {
+	out := ansi.Strip(renderMarkdown("| name | value |\n| --- | ---: |\n| a | 1 |\n| b | 22 |", 60, newStyles()))
+	for _, want := range []string{"name", "value", "a", "22", "│"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output %q is missing %q", out, want)
+		}
+	}
+}
+
+func TestMarkdownTableShortRows(t *testing.T) {
+	r := mdRenderer{styles: newStyles()}
+	if out := r.table([]string{"a | b"}, 40); len(out) == 0 {
+		t.Error("one-row table rendered nothing")
+	}
+}
@@ -0,0 +1,396 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	mergeMethodMerge  = "merge"
+	mergeMethodSquash = "squash"
+	mergeMethodRebase = "rebase"
+)
+
+type mergeOptions struct {
+	Methods             []string
+	DeleteBranchOnMerge bool
+}
+
+// autoMergeState is the auto-merge request GitHub holds for a pull request.
+type autoMergeState struct {
+	Method    string
+	EnabledBy string
+}
+
+type mergeOutcome struct {
+	SHA           string
+	DeletedBranch string
+	DeleteErr     error
+}
+
+// fetchMergeOptions reads which merge methods the repository allows.
+func fetchMergeOptions(ctx context.Context, token, repo string) (mergeOptions, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s", repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return mergeOptions{}, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return mergeOptions{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return mergeOptions{}, readAPIError(resp)
+	}
+
+	var payload struct {
+		AllowMergeCommit    *bool `json:"allow_merge_commit"`
+		AllowSquashMerge    *bool `json:"allow_squash_merge"`
+		AllowRebaseMerge    *bool `json:"allow_rebase_merge"`
+		DeleteBranchOnMerge bool  `json:"delete_branch_on_merge"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return mergeOptions{}, err
+	}
+
+	// The flags are only reported to users with push access; without them
+	// every method is offered and GitHub rejects the disallowed ones.
+	allowed := func(flag *bool) bool { return flag == nil || *flag }
+	opts := mergeOptions{DeleteBranchOnMerge: payload.DeleteBranchOnMerge}
+	if allowed(payload.AllowMergeCommit) {
+		opts.Methods = append(opts.Methods, mergeMethodMerge)
+	}
+	if allowed(payload.AllowSquashMerge) {
+		opts.Methods = append(opts.Methods, mergeMethodSquash)
+	}
+	if allowed(payload.AllowRebaseMerge) {
+		opts.Methods = append(opts.Methods, mergeMethodRebase)
+	}
+	if len(opts.Methods) == 0 {
+		return mergeOptions{}, errors.New("no merge methods are enabled for this repository")
+	}
+	return opts, nil
+}
+
+// mergePullRequest merges a pull request and, when asked, deletes its head
+// branch. A failed branch deletion does not undo the merge; it is reported
+// in the outcome instead.
+func mergePullRequest(ctx context.Context, token string, item detail, method, title, message string, deleteBranch bool) (mergeOutcome, error) {
+	if item.Repo == "" || item.Number == 0 {
+		return mergeOutcome{}, errors.New("missing repo or number")
+	}
+	payload := map[string]string{"merge_method": method}
+	if item.HeadSHA != "" {
+		payload["sha"] = item.HeadSHA
+	}
+	if method != mergeMethodRebase {
+		if title != "" {
+			payload["commit_title"] = title
+		}
+		if message != "" {
+			payload["commit_message"] = message
+		}
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return mergeOutcome{}, err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d/merge", item.Repo, item.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return mergeOutcome{}, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return mergeOutcome{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return mergeOutcome{}, readMergeError(resp, item)
+	}
+
+	var result struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return mergeOutcome{}, err
+	}
+	outcome := mergeOutcome{SHA: sanitizeLine(result.SHA)}
+	if deleteBranch {
+		if item.HeadRepo != item.Repo {
+			outcome.DeleteErr = errors.New("head branch lives in a fork")
+		} else {
+			outcome.DeleteErr = deleteBranchRef(ctx, token, item.Repo, item.HeadRef)
+			if outcome.DeleteErr == nil {
+				outcome.DeletedBranch = item.HeadRef
+			}
+		}
+	}
+	return outcome, nil
+}
+
+func deleteBranchRef(ctx context.Context, token, repo, branch string) error {
+	if branch == "" {
+		return errors.New("missing branch name")
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/git/refs/heads/%s", repo, url.PathEscape(branch))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 422 means the branch is already gone, e.g. deleted by the repository's
+	// auto-delete setting.
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
+
+// readMergeError turns a rejected merge into a message that says why the
+// pull request is blocked.
+func readMergeError(resp *http.Response, item detail) error {
+	body, _ := io.ReadAll(resp.Body)
+	var payload struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &payload)
+	message := sanitizeLine(payload.Message)
+	if message == "" {
+		message = resp.Status
+	}
+
+	switch resp.StatusCode {
+	case http.StatusConflict:
+		return fmt.Errorf("merge failed: the head branch changed, refresh and try again (%s)", message)
+	case http.StatusMethodNotAllowed, http.StatusUnprocessableEntity, http.StatusForbidden:
+		if reason := mergeBlockedReason(item); reason != "" {
+			return fmt.Errorf("merge blocked: %s (%s)", reason, message)
+		}
+		return fmt.Errorf("merge blocked: %s", message)
+	}
+	return fmt.Errorf("merge failed: %s", message)
+}
+
+// mergeBlockedReason explains GitHub's mergeable_state in plain words.
+func mergeBlockedReason(item detail) string {
+	switch item.MergeableState {
+	case "dirty":
+		return "the branch has merge conflicts"
+	case "blocked":
+		return "required reviews or status checks are not satisfied"
+	case "behind":
+		return "the head branch is out of date with the base branch"
+	case "unstable":
+		return "some status checks are failing"
+	case "draft":
+		return "the pull request is still a draft"
+	}
+	return ""
+}
+
+// defaultMergeText is the commit title and message GitHub would propose,
+// as "title\n\nmessage" for the composer.
+func defaultMergeText(item detail, method string) string {
+	switch method {
+	case mergeMethodSquash:
+		return fmt.Sprintf("%s (#%d)\n\n", item.Title, item.Number)
+	case mergeMethodMerge:
+		head := item.HeadRef
+		if owner, _, ok := strings.Cut(item.HeadRepo, "/"); ok {
+			head = owner + "/" + item.HeadRef
+		}
+		return fmt.Sprintf("Merge pull request #%d from %s\n\n%s", item.Number, head, item.Title)
+	}
+	return ""
+}
+
+// splitCommitText separates the first line of the composer text from the
+// rest, like git does for commit messages.
+func splitCommitText(text string) (string, string) {
+	title, message, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(message)
+}
+
+func nextMergeMethod(methods []string, current string) string {
+	for i, method := range methods {
+		if method == current {
+			return methods[(i+1)%len(methods)]
+		}
+	}
+	if len(methods) > 0 {
+		return methods[0]
+	}
+	return mergeMethodMerge
+}
+
+const enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!, $headline: String, $body: String) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method, commitHeadline: $headline, commitBody: $body}) {
+    clientMutationId
+  }
+}`
+
+const disableAutoMergeMutation = `mutation($id: ID!) {
+  disablePullRequestAutoMerge(input: {pullRequestId: $id}) {
+    clientMutationId
+  }
+}`
+
+// setAutoMerge enables auto-merge with the given method, or disables it
+// when method is empty.
+func setAutoMerge(ctx context.Context, token string, item detail, method, title, message string) error {
+	if item.NodeID == "" {
+		return errors.New("missing pull request node id")
+	}
+	if method == "" {
+		return postGraphQL(ctx, token, disableAutoMergeMutation, map[string]any{"id": item.NodeID}, nil)
+	}
+	variables := map[string]any{
+		"id":     item.NodeID,
+		"method": strings.ToUpper(method),
+	}
+	if method != mergeMethodRebase {
+		if title != "" {
+			variables["headline"] = title
+		}
+		if message != "" {
+			variables["body"] = message
+		}
+	}
+	return postGraphQL(ctx, token, enableAutoMergeMutation, variables, nil)
+}
+
+func formatAutoMerge(state *autoMergeState) string {
+	if state == nil {
+		return "off"
+	}
+	if state.EnabledBy != "" {
+		return fmt.Sprintf("%s by %s", state.Method, state.EnabledBy)
+	}
+	return state.Method
+}
+
+// startMerge checks that the pull request in the detail view can be merged
+// and opens the merge composer, loading the repository's merge settings
+// first if needed. With auto set, the composer queues an auto-merge
+// instead of merging right away.
+func (m *model) startMerge(auto bool) tea.Cmd {
+	item := m.detailItem
+	switch {
+	case item.Kind != "PR" || item.Title == "":
+		return nil
+	case item.State != "open":
+		m.status = "Only open pull requests can be merged"
+		m.statusOverride = true
+		return nil
+	case item.Draft:
+		m.status = "Error: merge blocked: the pull request is still a draft"
+		m.statusOverride = true
+		return nil
+	case item.MergeableState == "dirty":
+		m.status = "Error: merge blocked: " + mergeBlockedReason(item)
+		m.statusOverride = true
+		return nil
+	}
+	m.mergeAuto = auto
+	if opts, ok := m.mergeOptions[item.Repo]; ok {
+		m.openMergeComposer(opts)
+		return nil
+	}
+	m.actionLoading = true
+	m.status = "Loading merge options..."
+	m.statusOverride = true
+	return fetchMergeOptionsCmd(item.Repo)
+}
+
+func (m *model) openMergeComposer(opts mergeOptions) {
+	m.commentMode = true
+	m.composeKind = composeMerge
+	m.mergeMethods = opts.Methods
+	m.mergeMethod = opts.Methods[0]
+	m.mergeDeleteBranch = false
+	m.mergeAutoDelete = opts.DeleteBranchOnMerge
+	m.actionItem = m.detailTarget
+	m.textarea.Focus()
+	m.textarea.SetValue(defaultMergeText(m.detailItem, m.mergeMethod))
+}
+
+// cycleMergeMethod switches to the next allowed method. The commit text is
+// replaced only if it still holds the previous method's default.
+func (m *model) cycleMergeMethod() {
+	next := nextMergeMethod(m.mergeMethods, m.mergeMethod)
+	if m.textarea.Value() == defaultMergeText(m.detailItem, m.mergeMethod) {
+		m.textarea.SetValue(defaultMergeText(m.detailItem, next))
+	}
+	m.mergeMethod = next
+}
+
+func (m model) mergeComposerInfo() string {
+	info := fmt.Sprintf("%s • #%d • %s → %s", m.detailItem.Repo, m.detailItem.Number, m.detailItem.HeadRef, m.mergeMethod)
+	switch {
+	case m.mergeAuto:
+		info += " • merges once requirements are met"
+	case m.mergeAutoDelete:
+		info += " • branch is deleted by the repository"
+	case m.mergeDeleteBranch:
+		info += " • delete branch: yes"
+	default:
+		info += " • delete branch: no"
+	}
+	lines := []string{m.styles.MetaText.Render(info)}
+	if m.mergeMethod == mergeMethodRebase {
+		lines = append(lines, m.styles.MutedText.Render("Rebase merges keep the original commits; the text below is not used."))
+	} else {
+		lines = append(lines, m.styles.MutedText.Render("First line is the commit title, the rest is the message."))
+	}
+	if reason := mergeBlockedReason(m.detailItem); reason != "" && !m.mergeAuto {
+		lines = append(lines, m.styles.StatusErr.Render("Warning: "+reason))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m model) mergeConfirmView() string {
+	title, _ := splitCommitText(m.pendingBody)
+	prompt := fmt.Sprintf("Merge #%d using %s?", m.detailItem.Number, m.mergeMethod)
+	if m.mergeAuto {
+		prompt = fmt.Sprintf("Enable auto-merge for #%d using %s?", m.detailItem.Number, m.mergeMethod)
+	}
+	lines := []string{
+		prompt,
+		fmt.Sprintf("%s • %s", m.detailItem.Repo, m.detailItem.HeadRef),
+	}
+	if m.mergeMethod != mergeMethodRebase && title != "" {
+		lines = append(lines, "", ansi.Truncate(title, max(20, m.width-10), "…"))
+	}
+	if m.mergeDeleteBranch && !m.mergeAutoDelete && !m.mergeAuto {
+		lines = append(lines, "", "The head branch will be deleted.")
+	}
+	return m.styles.Confirm.Render(strings.Join(lines, "\n"))
+}
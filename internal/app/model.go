@@ -8,53 +8,140 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type model struct {
-	list               list.Model
-	filters            []filter
-	filterIndex        int
-	tabIndex           int
-	status             string
-	lastUpdated        time.Time
-	loading            bool
-	err                error
-	width              int
-	height             int
-	tokenMissing       bool
-	showDetail         bool
-	detailLoading      bool
-	detailErr          error
-	detailItem         detail
-	spinner            spinner.Model
-	textarea           textarea.Model
-	commentMode        bool
-	confirmMode        bool
-	actionLoading      bool
-	actionItem         issueItem
-	confirmTargetState string
-	statusOverride     bool
-	commentPage        int
-	styles             uiStyles
+	list                list.Model
+	items               []issueItem
+	grouped             bool
+	collapsed           map[string]bool
+	filters             []filter
+	filterIndex         int
+	tabIndex            int
+	status              string
+	lastUpdated         time.Time
+	loading             bool
+	err                 error
+	width               int
+	height              int
+	tokenMissing        bool
+	showDetail          bool
+	ciSeen              map[string]ciSeen
+	ciCache             map[string]string
+	layout              int
+	splitRatio          float64
+	previewSeq          int
+	detailTarget        issueItem
+	detailLoading       bool
+	detailErr           error
+	detailItem          detail
+	spinner             spinner.Model
+	textarea            textarea.Model
+	viewport            viewport.Model
+	diffViewport        viewport.Model
+	diffMode            bool
+	diffFiles           []diffFile
+	diffFor             string
+	diffLoading         bool
+	diffErr             error
+	diffIndex           int
+	diffHunks           []int
+	diffLines           []string
+	diffRefs            []diffLine
+	diffCursor          int
+	diffAnchor          int
+	diffSelecting       bool
+	pendingReviews      map[string][]pendingComment
+	pendingMode         bool
+	pendingIndex        int
+	draftComment        pendingComment
+	editingPending      int
+	mergeOptions        map[string]mergeOptions
+	mergeMethods        []string
+	mergeMethod         string
+	mergeDeleteBranch   bool
+	mergeAutoDelete     bool
+	mergeAuto           bool
+	picker              picker
+	pickerMode          bool
+	pickerKind          int
+	pickerLoading       int
+	pickerTarget        issueItem
+	repoLabels          map[string][]issueLabel
+	repoAssignees       map[string][]string
+	repoTeams           map[string][]string
+	viewer              string
+	commentMode         bool
+	composeKind         int
+	replyThread         reviewThread
+	selectedThread      string
+	selectedComment     int64
+	editingComment      int64
+	editorFile          string
+	drafts              *draftStore
+	draftSeq            int
+	sendingDraft        string
+	replies             []savedReply
+	previewMode         int
+	markdownSeq         int
+	markdownLoading     bool
+	markdownText        string
+	markdownFor         string
+	markdownErr         error
+	completion          *completion
+	completionDismissed string
+	completionSources   map[string]string
+	closeReason         string
+	pendingState        *pendingStateChange
+	stateSeq            int
+	recentRepos         []string
+	issueTemplates      map[string][]issueTemplate
+	blankIssues         map[string]bool
+	newIssue            *newIssueForm
+	repoRefs            map[string][]refSuggestion
+	repliesErr          error
+	expandedThreads     map[string]bool
+	confirmMode         bool
+	confirmAction       int
+	reviewEvent         string
+	pendingBody         string
+	actionLoading       bool
+	actionItem          issueItem
+	confirmTargetState  string
+	statusOverride      bool
+	commentPage         int
+	detailSection       int
+	timeline            []timelineEvent
+	timelinePage        commentPageInfo
+	timelineFor         string
+	timelineLoading     bool
+	timelineErr         error
+	styles              uiStyles
 }
 
 func newModel(l list.Model, styles uiStyles) model {
 	m := model{
-		list:        l,
-		filters:     filters,
-		filterIndex: 0,
-		tabIndex:    0,
-		status:      "Loading…",
-		loading:     true,
-		spinner:     spinner.New(spinner.WithSpinner(spinner.Line)),
-		commentPage: 1,
-		styles:      styles,
+		list:         l,
+		filters:      filters,
+		filterIndex:  0,
+		tabIndex:     0,
+		status:       "Loading…",
+		loading:      true,
+		spinner:      spinner.New(spinner.WithSpinner(spinner.Line)),
+		viewport:     viewport.New(0, 0),
+		diffViewport: viewport.New(0, 0),
+		commentPage:  1,
+		layout:       layoutSplit,
+		splitRatio:   splitDefault,
+		styles:       styles,
 	}
 	m.textarea = textarea.New()
 	m.textarea.Placeholder = "Write a comment..."
-	m.textarea.CharLimit = 4000
+	m.textarea.CharLimit = maxBodyChars
 	m.textarea.SetWidth(80)
 	m.textarea.SetHeight(10)
 	m.textarea.FocusedStyle.CursorLine = lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5"))
@@ -63,11 +150,15 @@ func newModel(l list.Model, styles uiStyles) model {
 	m.textarea.FocusedStyle.Prompt = lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Bold(true)
 	m.textarea.FocusedStyle.Base = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#bcc0cc"))
 	m.textarea.BlurredStyle = m.textarea.FocusedStyle
+	m.drafts = loadDraftStore()
+	m.replies, m.repliesErr = loadSavedReplies()
+	m.recentRepos = loadRecentRepos()
+	m.list.SetDelegate(itemDelegate{styles: styles, drafts: m.drafts})
 	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind), m.spinner.Tick)
+	return tea.Batch(fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind), viewerCmd(), m.spinner.Tick)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -75,77 +166,298 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width-2, msg.Height-7)
-		m.textarea.SetWidth(max(20, msg.Width-4))
-		m.textarea.SetHeight(max(6, msg.Height-10))
+		m.resize()
+		m.sizeComposer()
 		return m, nil
-		case tea.KeyMsg:
-			if m.commentMode {
-				switch msg.String() {
-				case "esc":
+	case tea.KeyMsg:
+		if m.pickerMode {
+			return m.updatePicker(msg)
+		}
+		if m.commentMode {
+			if cmd, ok := m.updateCompletionKey(msg); ok {
+				return m, cmd
+			}
+			switch msg.String() {
+			case "esc":
+				m.clearCompletion()
+				var cmd tea.Cmd
+				if m.draftable() {
+					m.noteDraft()
+					cmd = m.drafts.saveCmd()
+					if m.drafts.has(m.actionItem.Repo, m.actionItem.Number) {
+						m.status = "Draft saved"
+						m.statusOverride = true
+					}
+					m.refreshList()
+				}
+				if m.composeKind == composeIssueField || m.composeKind == composeNewIssue {
+					m.newIssue = nil
+				}
+				m.commentMode = false
+				m.textarea.Blur()
+				m.textarea.SetValue("")
+				m.discardEditorFile()
+				return m, cmd
+			case "ctrl+o":
+				return m, m.openEditor()
+			case "ctrl+y":
+				return m, m.cyclePreview()
+			case "ctrl+r":
+				m.openRepliesPicker()
+				return m, nil
+			case "ctrl+s":
+				m.openSaveReply()
+				return m, nil
+			case "ctrl+t":
+				switch m.composeKind {
+				case composeClose:
+					if m.closeReason != "" {
+						m.closeReason = nextCloseReason(m.closeReason)
+					}
+				case composeReview:
+					m.reviewEvent = nextReviewEvent(m.reviewEvent)
+				case composeMerge:
+					m.cycleMergeMethod()
+				}
+				return m, nil
+			case "ctrl+b":
+				if m.composeKind == composeMerge && !m.mergeAuto {
+					m.mergeDeleteBranch = !m.mergeDeleteBranch
+				}
+				return m, nil
+			case "ctrl+g":
+				m.clearCompletion()
+				body := strings.TrimSpace(m.textarea.Value())
+				if m.composeKind == composeLineComment {
+					if body == "" {
+						m.status = "Comment is empty"
+						m.statusOverride = true
+						return m, nil
+					}
 					m.commentMode = false
 					m.textarea.Blur()
 					m.textarea.SetValue("")
+					m.discardEditorFile()
+					m.savePendingComment(body)
 					return m, nil
-				case "ctrl+g":
-					body := strings.TrimSpace(m.textarea.Value())
-					if body == "" {
-						m.status = "Comment is empty"
+				}
+				if m.composeKind == composeClose {
+					return m, m.submitClose(body)
+				}
+				if m.composeKind == composeIssueField {
+					m.discardEditorFile()
+					return m, m.submitIssueField(body)
+				}
+				if m.composeKind == composeNewIssue {
+					return m, m.submitNewIssue(m.textarea.Value())
+				}
+				if m.composeKind == composeMerge {
+					m.commentMode = false
+					m.textarea.Blur()
+					m.pendingBody = body
+					m.confirmMode = true
+					m.confirmAction = confirmMerge
+					return m, nil
+				}
+				if m.composeKind == composeReview {
+					if body == "" && reviewNeedsBody(m.reviewEvent, len(m.pendingReviews[m.actionItem.URL])) {
+						m.status = "Review body is required unless approving"
 						m.statusOverride = true
 						return m, nil
+					}
+					m.commentMode = false
+					m.textarea.Blur()
+					m.pendingBody = body
+					m.confirmMode = true
+					m.confirmAction = confirmReview
+					return m, nil
+				}
+				if body == "" {
+					m.status = "Comment is empty"
+					m.statusOverride = true
+					return m, nil
+				}
+				if m.draftable() {
+					m.noteDraft()
+					m.sendingDraft = draftKey(m.actionItem.Repo, m.actionItem.Number)
 				}
 				m.commentMode = false
 				m.textarea.Blur()
 				m.textarea.SetValue("")
 				m.actionLoading = true
-				m.status = fmt.Sprintf("Sending comment to %s#%d...", m.actionItem.Repo, m.actionItem.Number)
 				m.statusOverride = true
+				if m.composeKind == composeThreadReply {
+					m.status = fmt.Sprintf("Replying on %s...", m.replyThread.location())
+					return m, replyThreadCmd(m.actionItem, m.replyThread, body)
+				}
+				if m.composeKind == composeEditComment {
+					m.status = fmt.Sprintf("Updating comment on %s#%d...", m.actionItem.Repo, m.actionItem.Number)
+					return m, editCommentCmd(m.actionItem, m.editingComment, body)
+				}
+				m.status = fmt.Sprintf("Sending comment to %s#%d...", m.actionItem.Repo, m.actionItem.Number)
 				return m, postCommentCmd(m.actionItem, body)
 			}
+			if m.previewHidesEditor() {
+				m.status = "Preview shown; ctrl+y to keep editing"
+				m.statusOverride = true
+				return m, nil
+			}
 			var cmd tea.Cmd
 			m.textarea, cmd = m.textarea.Update(msg)
-			return m, cmd
+			return m, tea.Batch(cmd, m.noteDraft(), m.notePreviewEdit(), m.updateCompletion())
 		}
 		if m.confirmMode {
 			switch msg.String() {
 			case "y":
 				m.confirmMode = false
-				m.actionLoading = true
-				m.status = actionProgress(m.confirmTargetState)
-				return m, updateIssueStateCmd(m.actionItem, m.confirmTargetState)
+				switch m.confirmAction {
+				case confirmReview:
+					m.actionLoading = true
+					m.textarea.SetValue("")
+					m.status = fmt.Sprintf("Submitting review to %s#%d...", m.actionItem.Repo, m.actionItem.Number)
+					m.statusOverride = true
+					return m, submitReviewCmd(m.actionItem, m.reviewEvent, m.pendingBody, m.pendingReviews[m.actionItem.URL])
+				case confirmMerge:
+					m.actionLoading = true
+					m.textarea.SetValue("")
+					title, message := splitCommitText(m.pendingBody)
+					m.statusOverride = true
+					if m.mergeAuto {
+						m.status = fmt.Sprintf("Enabling auto-merge for %s#%d...", m.detailItem.Repo, m.detailItem.Number)
+						return m, setAutoMergeCmd(m.detailItem, m.mergeMethod, title, message)
+					}
+					m.status = fmt.Sprintf("Merging %s#%d...", m.detailItem.Repo, m.detailItem.Number)
+					return m, mergeCmd(m.detailItem, m.mergeMethod, title, message, m.mergeDeleteBranch && !m.mergeAutoDelete)
+				case confirmDisableAutoMerge:
+					m.actionLoading = true
+					m.status = fmt.Sprintf("Disabling auto-merge for %s#%d...", m.detailItem.Repo, m.detailItem.Number)
+					m.statusOverride = true
+					return m, setAutoMergeCmd(m.detailItem, "", "", "")
+				case confirmDeleteComment:
+					m.actionLoading = true
+					m.status = fmt.Sprintf("Deleting comment on %s#%d...", m.actionItem.Repo, m.actionItem.Number)
+					m.statusOverride = true
+					return m, deleteCommentCmd(m.actionItem, m.editingComment)
+				case confirmDiscardReview:
+					delete(m.pendingReviews, m.detailTarget.URL)
+					m.pendingMode = false
+					if m.diffMode {
+						m.refreshDiffContent()
+					}
+					m.status = "Pending review discarded"
+					m.statusOverride = true
+					return m, nil
+				}
+				if m.stateChangeBusy() {
+					return m, nil
+				}
+				change := stateChange{Item: m.actionItem, State: m.confirmTargetState}
+				if change.State == "open" {
+					change.Reason = "reopened"
+				}
+				return m, m.scheduleStateChange(change)
 			case "n", "esc":
 				m.confirmMode = false
+				if m.confirmAction == confirmReview || m.confirmAction == confirmMerge {
+					m.commentMode = true
+					m.textarea.Focus()
+				}
 				return m, nil
 			}
 			return m, nil
 		}
+		if m.pendingMode {
+			return m.updatePending(msg)
+		}
+		if m.diffMode {
+			return m.updateDiff(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 		case "esc":
+			if m.showDetail && (m.selectedThread != "" || m.selectedComment != 0) {
+				m.selectedThread = ""
+				m.selectedComment = 0
+				m.syncDetailViewport()
+				return m, nil
+			}
 			if m.showDetail {
 				m.showDetail = false
 				m.detailErr = nil
-				return m, nil
+				m.resize()
+				return m, m.schedulePreview()
 			}
 		case "j", "down":
-			if !m.showDetail {
-				m.list.CursorDown()
+			if m.showDetail {
+				m.scrollDetail(msg.String())
+				return m, nil
 			}
-			return m, nil
+			m.moveCursor(1)
+			return m, m.schedulePreview()
 		case "k", "up":
-			if !m.showDetail {
-				m.list.CursorUp()
+			if m.showDetail {
+				m.scrollDetail(msg.String())
+				return m, nil
+			}
+			m.moveCursor(-1)
+			return m, m.schedulePreview()
+		case "pgdown", "pgup", "g", "G", "home", "end":
+			if m.showDetail {
+				m.scrollDetail(msg.String())
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			if msg.String() == "pgup" || msg.String() == "g" || msg.String() == "home" {
+				m.skipGroupHeader(-1)
+			} else {
+				m.skipGroupHeader(1)
+			}
+			return m, tea.Batch(cmd, m.schedulePreview())
+		case "ctrl+d", "ctrl+u":
+			if m.detailVisible() {
+				m.scrollDetail(msg.String())
+			}
+			return m, nil
+		case "s":
+			if m.showDetail {
+				return m, nil
+			}
+			m.grouped = !m.grouped
+			m.refreshList()
+			return m, m.schedulePreview()
+		case " ":
+			if !m.showDetail && m.grouped {
+				m.toggleGroup()
+			}
+			return m, m.schedulePreview()
+		case "z":
+			if !m.showDetail && m.grouped {
+				m.toggleAllGroups()
+			}
+			return m, m.schedulePreview()
+		case "v":
+			return m, m.cycleLayout()
+		case "<":
+			if m.splitActive() {
+				m.resizeSplit(-splitStep)
+			}
+			return m, nil
+		case ">":
+			if m.splitActive() {
+				m.resizeSplit(splitStep)
 			}
 			return m, nil
 		case "r":
 			if m.showDetail {
-				if item, ok := m.list.SelectedItem().(issueItem); ok {
-					m.detailLoading = true
-					m.detailErr = nil
-					return m, fetchDetailCmd(item, m.commentPage)
+				if m.detailTarget.URL == "" {
+					return m, nil
 				}
-				return m, nil
+				if m.detailSection == sectionTimeline {
+					return m, tea.Batch(m.loadDetail(m.detailTarget, m.commentPage), m.loadTimeline(m.detailTarget, max(1, m.timelinePage.Page)))
+				}
+				return m, m.loadDetail(m.detailTarget, m.commentPage)
 			}
 			m.loading = true
 			m.status = "Refreshing..."
@@ -155,6 +467,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.filterIndex = (m.filterIndex + 1) % len(m.filters)
 				m.loading = true
 				m.status = "Loading..."
+				m.items = nil
 				m.list.SetItems(nil)
 				return m, fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind)
 			}
@@ -164,13 +477,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.tabIndex = (m.tabIndex + 1) % len(tabs)
 				m.loading = true
 				m.status = "Loading..."
+				m.items = nil
 				m.list.SetItems(nil)
 				return m, fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind)
 			}
 			return m, nil
 		case "c":
+			if m.showDetail && m.selectedComment != 0 {
+				m.startQuoteReply()
+				return m, nil
+			}
 			if m.showDetail && m.detailItem.Title != "" {
 				m.commentMode = true
+				m.composeKind = composeComment
+				if i := threadIndex(m.detailItem.Threads, m.selectedThread); i >= 0 {
+					m.composeKind = composeThreadReply
+					m.replyThread = m.detailItem.Threads[i]
+				}
 				m.actionItem = issueItem{
 					TitleText: m.detailItem.Title,
 					Repo:      m.detailItem.Repo,
@@ -180,58 +503,179 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.textarea.Focus()
 				m.textarea.SetValue("")
+				if m.composeKind == composeComment {
+					m.restoreDraft("")
+				}
 				return m, nil
 			}
 			if item, ok := m.list.SelectedItem().(issueItem); ok {
 				m.commentMode = true
+				m.composeKind = composeComment
 				m.actionItem = item
 				m.textarea.Focus()
-				m.textarea.SetValue("")
+				m.restoreDraft("")
 				return m, nil
 			}
 			return m, nil
 		case "n":
+			if m.showDetail && m.detailSection == sectionTimeline {
+				if m.timelinePage.HasNext && !m.timelineLoading {
+					return m, m.loadTimeline(m.detailTarget, m.timelinePage.Page+1)
+				}
+				return m, nil
+			}
 			if m.showDetail && m.detailItem.HasNextComments {
-				m.commentPage = m.detailItem.CommentPage + 1
-				m.detailLoading = true
-				m.detailErr = nil
-				return m, fetchDetailCmd(m.actionItem, m.commentPage)
+				return m, m.loadDetail(m.detailTarget, m.detailItem.CommentPage+1)
 			}
 			return m, nil
 		case "p":
+			if m.showDetail && m.detailSection == sectionTimeline {
+				if m.timelinePage.HasPrev && !m.timelineLoading {
+					return m, m.loadTimeline(m.detailTarget, max(1, m.timelinePage.Page-1))
+				}
+				return m, nil
+			}
 			if m.showDetail && m.detailItem.HasPrevComments {
-				m.commentPage = m.detailItem.CommentPage - 1
-				if m.commentPage < 1 {
-					m.commentPage = 1
+				return m, m.loadDetail(m.detailTarget, max(1, m.detailItem.CommentPage-1))
+			}
+			return m, nil
+		case "t":
+			if !m.showDetail || m.detailTarget.URL == "" {
+				return m, nil
+			}
+			m.selectedComment = 0
+			if m.detailSection == sectionTimeline {
+				m.detailSection = sectionComments
+				m.syncDetailViewport()
+				m.viewport.GotoTop()
+				return m, nil
+			}
+			m.detailSection = sectionTimeline
+			var cmd tea.Cmd
+			if m.timelineFor != m.detailTarget.URL {
+				cmd = m.loadTimeline(m.detailTarget, 1)
+			}
+			m.syncDetailViewport()
+			m.viewport.GotoTop()
+			return m, cmd
+		case "R":
+			if m.showDetail {
+				m.startReview()
+			}
+			return m, nil
+		case "P":
+			if m.showDetail {
+				m.openPendingReview()
+			}
+			return m, nil
+		case "M":
+			if m.showDetail {
+				return m, m.startMerge(false)
+			}
+			return m, nil
+		case "L":
+			return m, m.startLabelPicker()
+		case "w":
+			m.openDraftsPicker()
+			return m, nil
+		case "a":
+			return m, m.startPeoplePicker(pickAssignees)
+		case "W":
+			return m, m.startPeoplePicker(pickReviewers)
+		case "I":
+			return m, m.assignMe()
+		case "U":
+			return m, m.removeMeAsReviewer()
+		case "A":
+			if !m.showDetail || m.detailItem.Kind != "PR" || m.detailItem.Title == "" {
+				return m, nil
+			}
+			if m.detailItem.AutoMerge != nil {
+				m.confirmMode = true
+				m.confirmAction = confirmDisableAutoMerge
+				m.actionItem = m.detailTarget
+				return m, nil
+			}
+			return m, m.startMerge(true)
+		case "J", "K":
+			if !m.showDetail || m.detailItem.Title == "" {
+				return m, nil
+			}
+			m.stepSelection(msg.String() == "J")
+			return m, nil
+		case "D":
+			if m.showDetail && m.selectedComment != 0 {
+				return m, m.startDeleteComment()
+			}
+			return m, nil
+		case "e":
+			if m.showDetail && m.selectedComment != 0 {
+				return m, m.startEditComment()
+			}
+			if m.showDetail && m.selectedThread != "" {
+				if m.expandedThreads == nil {
+					m.expandedThreads = make(map[string]bool)
 				}
-				m.detailLoading = true
-				m.detailErr = nil
-				return m, fetchDetailCmd(m.actionItem, m.commentPage)
+				m.expandedThreads[m.selectedThread] = !m.expandedThreads[m.selectedThread]
+				m.syncDetailViewport()
+				m.revealLine(m.selectionAnchor())
 			}
 			return m, nil
 		case "x":
 			if m.showDetail && m.detailItem.Title != "" {
-				m.confirmMode = true
-				m.actionItem = issueItem{
+				item := issueItem{
 					TitleText: m.detailItem.Title,
 					Repo:      m.detailItem.Repo,
 					Number:    m.detailItem.Number,
 					URL:       m.detailItem.URL,
 					Kind:      m.detailItem.Kind,
+					Author:    m.detailItem.Author,
 				}
 				if m.detailItem.State == "closed" {
+					m.confirmMode = true
+					m.confirmAction = confirmState
+					m.actionItem = item
 					m.confirmTargetState = "open"
-				} else {
-					m.confirmTargetState = "closed"
+					return m, nil
 				}
+				m.startClose(item)
 				return m, nil
 			}
 			if item, ok := m.list.SelectedItem().(issueItem); ok {
-				m.confirmMode = true
-				m.actionItem = item
-				m.confirmTargetState = "closed"
+				if item.State == "closed" {
+					m.confirmMode = true
+					m.confirmAction = confirmState
+					m.actionItem = item
+					m.confirmTargetState = "open"
+					return m, nil
+				}
+				m.startClose(item)
+				return m, nil
+			}
+			return m, nil
+		case "u":
+			return m, m.undoStateChange()
+		case "N":
+			m.openRepoPicker()
+			return m, nil
+		case "d":
+			if !m.showDetail || m.detailItem.Kind != "PR" || m.detailItem.URL != m.detailTarget.URL {
 				return m, nil
 			}
+			m.diffMode = true
+			if m.diffFor != m.detailTarget.URL || m.diffErr != nil {
+				return m, m.loadDiff(m.detailTarget)
+			}
+			m.syncDiffViewport()
+			return m, nil
+		case "O":
+			if m.showDetail && m.detailItem.Kind == "PR" {
+				if check, ok := firstFailingCheck(m.detailItem.Checks); ok {
+					return m, openURLCmd(check.URL)
+				}
+				m.status = "No failing checks"
+				m.statusOverride = true
+			}
 			return m, nil
 		case "o":
 			if item, ok := m.list.SelectedItem().(issueItem); ok {
@@ -239,12 +683,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "enter":
+			if _, ok := m.list.SelectedItem().(groupHeader); ok {
+				m.toggleGroup()
+				return m, nil
+			}
 			if item, ok := m.list.SelectedItem().(issueItem); ok {
 				m.showDetail = true
-				m.commentPage = 1
-				m.detailLoading = true
-				m.detailErr = nil
-				return m, fetchDetailCmd(item, m.commentPage)
+				m.resize()
+				if item.URL == m.detailTarget.URL && m.detailItem.Title != "" && !m.detailLoading {
+					return m, nil
+				}
+				return m, tea.Batch(m.noteRecentRepo(item.Repo), m.loadDetail(item, 1))
 			}
 			return m, nil
 		}
@@ -257,16 +706,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		items := make([]list.Item, 0, len(msg.items))
-		for _, item := range msg.items {
-			items = append(items, item)
-		}
-		m.list.SetItems(items)
+		m.items = msg.items
+		m.refreshList()
 		m.lastUpdated = time.Now()
-		m.status = fmt.Sprintf("Loaded %d items • updated %s", len(items), humanizeSince(m.lastUpdated))
+		m.status = fmt.Sprintf("Loaded %d items • updated %s", len(m.items), humanizeSince(m.lastUpdated))
 		m.statusOverride = false
+		checks := m.checksCmd()
+		return m, tea.Batch(checks, m.schedulePreview())
+	case checksResult:
+		m.applyChecks(msg)
 		return m, nil
+	case previewTickMsg:
+		return m, m.handlePreviewTick(msg)
 	case detailResult:
+		if msg.target.URL != m.detailTarget.URL {
+			return m, nil
+		}
 		m.detailLoading = false
 		m.detailErr = msg.err
 		if msg.err != nil {
@@ -274,42 +729,310 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusOverride = true
 			return m, nil
 		}
+		samePage := m.detailItem.URL == msg.item.URL && m.detailItem.CommentPage == msg.item.CommentPage
+		if m.detailItem.URL != msg.item.URL || threadIndex(msg.item.Threads, m.selectedThread) < 0 {
+			m.selectedThread = ""
+		}
+		if m.detailItem.URL != msg.item.URL || commentIndex(msg.item.CommentList, m.selectedComment) < 0 {
+			m.selectedComment = 0
+		}
+		if m.detailItem.URL != msg.item.URL {
+			m.expandedThreads = nil
+		}
 		m.detailItem = msg.item
 		m.commentPage = msg.item.CommentPage
+		m.syncDetailViewport()
+		if !samePage {
+			m.viewport.GotoTop()
+		}
+		return m, nil
+	case timelineResult:
+		if msg.target.URL != m.timelineFor {
+			return m, nil
+		}
+		m.timelineLoading = false
+		m.timelineErr = msg.err
+		if msg.err == nil {
+			samePage := m.timelinePage.Page == msg.page.Page
+			m.timeline = msg.events
+			m.timelinePage = msg.page
+			m.syncDetailViewport()
+			if !samePage {
+				m.viewport.GotoTop()
+			}
+			return m, nil
+		}
+		m.syncDetailViewport()
+		return m, nil
+	case diffResult:
+		if msg.target.URL != m.diffFor {
+			return m, nil
+		}
+		m.diffLoading = false
+		m.diffErr = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		refresh := len(m.diffFiles) > 0
+		m.diffFiles = msg.files
+		m.diffIndex = min(m.diffIndex, max(0, len(m.diffFiles)-1))
+		m.syncDiffViewport()
+		if !refresh {
+			m.diffViewport.GotoTop()
+		}
 		return m, nil
 	case commentResult:
 		m.actionLoading = false
+		kept := m.settleEditorFile(msg.err)
 		if msg.err != nil {
-			m.status = fmt.Sprintf("Error: %s", msg.err.Error())
+			if m.sendingDraft != "" {
+				kept = " (kept as draft)"
+			}
+			m.sendingDraft = ""
+			m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), kept)
 			m.statusOverride = true
 			return m, nil
 		}
 		m.status = fmt.Sprintf("Comment posted to %s#%d", m.actionItem.Repo, m.actionItem.Number)
 		m.statusOverride = true
-		if m.showDetail {
-			m.detailLoading = true
-			return m, fetchDetailCmd(m.actionItem, m.commentPage)
+		cmd := m.clearSentDraft()
+		if m.detailVisible() && m.actionItem.URL == m.detailTarget.URL {
+			return m, tea.Batch(cmd, m.loadDetail(m.detailTarget, m.commentPage))
+		}
+		return m, cmd
+	case commentEditResult:
+		m.actionLoading = false
+		kept := m.settleEditorFile(msg.err)
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), kept)
+			m.statusOverride = true
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Comment updated on %s#%d", m.actionItem.Repo, m.actionItem.Number)
+		if msg.deleted {
+			m.status = fmt.Sprintf("Comment deleted from %s#%d", m.actionItem.Repo, m.actionItem.Number)
+		}
+		m.statusOverride = true
+		if m.detailVisible() && m.actionItem.URL == m.detailTarget.URL {
+			return m, m.loadDetail(m.detailTarget, m.commentPage)
 		}
 		return m, nil
-	case stateResult:
+	case reviewResult:
+		m.actionLoading = false
+		kept := m.settleEditorFile(msg.err)
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), kept)
+			m.statusOverride = true
+			m.reopenComposer(composeReview)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Review submitted to %s#%d (%s)", m.actionItem.Repo, m.actionItem.Number, strings.ToLower(reviewEventLabel(msg.event)))
+		m.statusOverride = true
+		delete(m.pendingReviews, m.actionItem.URL)
+		m.pendingMode = false
+		if m.diffMode {
+			m.refreshDiffContent()
+		}
+		if m.detailVisible() && m.actionItem.URL == m.detailTarget.URL {
+			return m, m.loadDetail(m.detailTarget, m.commentPage)
+		}
+		return m, nil
+	case mergeOptionsResult:
 		m.actionLoading = false
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Error: %s", msg.err.Error())
 			m.statusOverride = true
 			return m, nil
 		}
+		if m.mergeOptions == nil {
+			m.mergeOptions = make(map[string]mergeOptions)
+		}
+		m.mergeOptions[msg.repo] = msg.options
+		m.status = fmt.Sprintf("Merge methods: %s", strings.Join(msg.options.Methods, ", "))
+		if m.showDetail && m.detailItem.Repo == msg.repo && !m.commentMode && !m.confirmMode {
+			m.openMergeComposer(msg.options)
+		}
+		return m, nil
+	case mergeResult:
+		m.actionLoading = false
+		kept := m.settleEditorFile(msg.err)
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), kept)
+			m.statusOverride = true
+			m.reopenComposer(composeMerge)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Merged %s#%d (%s)", m.actionItem.Repo, m.actionItem.Number, msg.method)
+		switch {
+		case msg.outcome.DeleteErr != nil:
+			m.status += fmt.Sprintf(" • branch not deleted: %s", msg.outcome.DeleteErr.Error())
+		case msg.outcome.DeletedBranch != "":
+			m.status += fmt.Sprintf(" • deleted branch %s", msg.outcome.DeletedBranch)
+		}
+		m.statusOverride = true
+		m.loading = true
+		if m.detailVisible() && m.actionItem.URL == m.detailTarget.URL {
+			return m, tea.Batch(fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind), m.loadDetail(m.detailTarget, m.commentPage))
+		}
+		return m, fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind)
+	case repliesSavedResult:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: saving replies: %s", msg.err.Error())
+		} else {
+			m.status = fmt.Sprintf("Saved reply %q", msg.name)
+		}
+		m.statusOverride = true
+		return m, nil
+	case completionSourceResult:
+		return m, m.handleCompletionSource(msg)
+	case markdownTickMsg:
+		return m, m.handleMarkdownTick(msg)
+	case markdownResult:
+		m.handleMarkdownResult(msg)
+		return m, nil
+	case draftTickMsg:
+		return m, m.handleDraftTick(msg)
+	case issueTemplatesResult:
+		return m, m.handleIssueTemplates(msg)
+	case issueCreatedResult:
+		return m, m.handleIssueCreated(msg)
+	case recentReposSavedResult:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: saving recent repositories: %s", msg.err.Error())
+			m.statusOverride = true
+		}
+		return m, nil
+	case draftsSavedResult:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: saving drafts: %s", msg.err.Error())
+			m.statusOverride = true
+		}
+		return m, nil
+	case editorResult:
+		if m.commentMode {
+			m.loadEditorResult(msg)
+			return m, tea.Batch(m.noteDraft(), m.notePreviewEdit())
+		}
+		return m, nil
+	case labelsResult:
+		m.actionLoading = false
+		if msg.err != nil {
+			m.pickerLoading = pickNone
+			m.status = fmt.Sprintf("Error: %s", msg.err.Error())
+			m.statusOverride = true
+			return m, nil
+		}
+		if m.repoLabels == nil {
+			m.repoLabels = make(map[string][]issueLabel)
+		}
+		m.repoLabels[msg.repo] = msg.labels
+		m.status = fmt.Sprintf("Loaded %d labels for %s", len(msg.labels), msg.repo)
+		if m.pickerLoading == pickLabels && m.pickerTarget.Repo == msg.repo && !m.commentMode && !m.confirmMode {
+			m.openLabelPicker(msg.labels)
+		}
+		m.pickerLoading = pickNone
+		return m, nil
+	case labelsSetResult:
+		m.actionLoading = false
+		if msg.err != nil {
+			m.setItemLabels(msg.target.URL, msg.previous)
+			m.status = fmt.Sprintf("Error: %s", msg.err.Error())
+			m.statusOverride = true
+			return m, nil
+		}
+		m.setItemLabels(msg.target.URL, msg.labels)
+		m.status = fmt.Sprintf("Labels updated on %s#%d", msg.target.Repo, msg.target.Number)
+		m.statusOverride = true
+		return m, nil
+	case viewerResult:
+		if msg.err == nil {
+			m.viewer = msg.login
+		}
+		return m, nil
+	case peopleResult:
+		m.actionLoading = false
+		if msg.err != nil {
+			m.pickerLoading = pickNone
+			m.status = fmt.Sprintf("Error: %s", msg.err.Error())
+			m.statusOverride = true
+			return m, nil
+		}
+		if m.repoAssignees == nil {
+			m.repoAssignees = make(map[string][]string)
+			m.repoTeams = make(map[string][]string)
+		}
+		m.repoAssignees[msg.repo] = msg.users
+		if msg.teams != nil {
+			m.repoTeams[msg.repo] = msg.teams
+		}
+		m.status = fmt.Sprintf("Loaded %d assignable users for %s", len(msg.users), msg.repo)
+		if (m.pickerLoading == pickAssignees || m.pickerLoading == pickReviewers) && m.pickerTarget.Repo == msg.repo && !m.commentMode && !m.confirmMode {
+			m.openPeoplePicker(m.pickerLoading)
+		}
+		m.pickerLoading = pickNone
+		return m, nil
+	case peopleUpdatedResult:
+		m.actionLoading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %s", msg.err.Error())
+			m.statusOverride = true
+			return m, nil
+		}
+		m.status = msg.message
+		m.statusOverride = true
+		if m.detailVisible() && msg.target.URL == m.detailTarget.URL {
+			return m, m.loadDetail(m.detailTarget, m.commentPage)
+		}
+		return m, nil
+	case autoMergeResult:
+		m.actionLoading = false
+		kept := m.settleEditorFile(msg.err)
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), kept)
+			m.statusOverride = true
+			if msg.method != "" {
+				m.reopenComposer(composeMerge)
+			}
+			return m, nil
+		}
+		if msg.method == "" {
+			m.status = fmt.Sprintf("Auto-merge disabled for %s#%d", m.actionItem.Repo, m.actionItem.Number)
+		} else {
+			m.status = fmt.Sprintf("Auto-merge (%s) enabled for %s#%d", msg.method, m.actionItem.Repo, m.actionItem.Number)
+		}
+		m.statusOverride = true
+		if m.detailVisible() && m.actionItem.URL == m.detailTarget.URL {
+			return m, m.loadDetail(m.detailTarget, m.commentPage)
+		}
+		return m, nil
+	case stateResult:
+		m.actionLoading = false
+		kept := m.settleStateText(msg)
+		if msg.err != nil {
+			m.finishStateChange(msg)
+			m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), kept)
+			m.statusOverride = true
+			return m, nil
+		}
 		if msg.state == "closed" {
 			m.status = "Closed"
 		} else {
 			m.status = "Reopened"
 		}
 		m.statusOverride = true
+		undo := m.finishStateChange(msg)
 		m.loading = true
-		if m.showDetail {
-			m.detailLoading = true
-			return m, tea.Batch(fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind), fetchDetailCmd(m.actionItem, m.commentPage))
+		if m.detailVisible() && msg.change.Item.URL == m.detailTarget.URL {
+			return m, tea.Batch(undo, fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind), m.loadDetail(m.detailTarget, m.commentPage))
 		}
-		return m, fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind)
+		return m, tea.Batch(undo, fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind))
+	case stateTickMsg:
+		return m, m.handleStateTick(msg)
+	case undoExpiredMsg:
+		if m.pendingState != nil && m.pendingState.seq == msg.seq && m.pendingState.done {
+			m.pendingState = nil
+		}
+		return m, nil
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -332,8 +1055,20 @@ func (m model) View() string {
 	tabsLine := renderTabs(tabs, m.tabIndex, m.styles)
 
 	body := m.list.View()
+	if m.splitActive() {
+		body = m.splitView(body)
+	}
 	if m.showDetail {
-		body = m.detailView()
+		body = m.detailView(m.width - 2)
+	}
+	if m.diffMode {
+		body = m.diffView()
+	}
+	if m.pendingMode {
+		body = m.pendingView()
+	}
+	if m.pickerMode {
+		body = m.picker.View(m.width-2, m.bodyHeight(), m.styles)
 	}
 	if m.confirmMode {
 		body = m.confirmView()
@@ -341,42 +1076,108 @@ func (m model) View() string {
 	if m.commentMode {
 		body = m.commentView()
 	}
-	if m.loading && !m.showDetail && !m.confirmMode && !m.commentMode {
+	if m.loading && !m.showDetail && !m.confirmMode && !m.commentMode && !m.pickerMode {
 		body = fmt.Sprintf("%s %s", m.spinner.View(), m.styles.MutedText.Render("Loading list..."))
 	}
 
 	hotkeyStyle := m.styles.HelpKey
 	helpTextStyle := m.styles.HelpText
 	help := fmt.Sprintf(
-		"%s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
+		"%s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
 		hotkeyStyle.Render("↑/↓ j/k"), helpTextStyle.Render("navigate"),
 		hotkeyStyle.Render("enter"), helpTextStyle.Render("details"),
 		hotkeyStyle.Render("o"), helpTextStyle.Render("open"),
 		hotkeyStyle.Render("r"), helpTextStyle.Render("refresh"),
 		hotkeyStyle.Render("f"), helpTextStyle.Render("filter"),
 		hotkeyStyle.Render("tab"), helpTextStyle.Render("switch"),
+		hotkeyStyle.Render("s"), helpTextStyle.Render("group"),
 		hotkeyStyle.Render("c"), helpTextStyle.Render("comment"),
 		hotkeyStyle.Render("x"), helpTextStyle.Render("close/reopen"),
+		hotkeyStyle.Render("N"), helpTextStyle.Render("new issue"),
+		hotkeyStyle.Render("v"), helpTextStyle.Render("layout"),
 		hotkeyStyle.Render("q"), helpTextStyle.Render("quit"),
 	)
+	if m.splitActive() {
+		help += fmt.Sprintf("  %s %s  %s %s",
+			hotkeyStyle.Render("</>"), helpTextStyle.Render("resize"),
+			hotkeyStyle.Render("ctrl+d/u"), helpTextStyle.Render("scroll preview"),
+		)
+	}
+	if m.grouped && !m.showDetail {
+		help += fmt.Sprintf("  %s %s",
+			hotkeyStyle.Render("space/z"), helpTextStyle.Render("fold"),
+		)
+	}
 	if m.showDetail {
 		help = fmt.Sprintf(
-			"%s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
+			"%s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
 			hotkeyStyle.Render("esc"), helpTextStyle.Render("back"),
+			hotkeyStyle.Render("j/k g/G"), helpTextStyle.Render("scroll"),
 			hotkeyStyle.Render("o"), helpTextStyle.Render("open"),
 			hotkeyStyle.Render("r"), helpTextStyle.Render("refresh"),
 			hotkeyStyle.Render("c"), helpTextStyle.Render("comment"),
 			hotkeyStyle.Render("x"), helpTextStyle.Render("close/reopen"),
-			hotkeyStyle.Render("n/p"), helpTextStyle.Render("comments"),
+			hotkeyStyle.Render("n/p"), helpTextStyle.Render("page"),
+			hotkeyStyle.Render("t"), helpTextStyle.Render("timeline"),
+			hotkeyStyle.Render("J/K"), helpTextStyle.Render("select"),
+			hotkeyStyle.Render("e/D"), helpTextStyle.Render("edit/delete mine"),
+			hotkeyStyle.Render("L"), helpTextStyle.Render("labels"),
+			hotkeyStyle.Render("a"), helpTextStyle.Render("assignees"),
+			hotkeyStyle.Render("I"), helpTextStyle.Render("assign me"),
 			hotkeyStyle.Render("q"), helpTextStyle.Render("quit"),
 		)
+		if m.detailItem.Kind == "PR" {
+			help += fmt.Sprintf("  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
+				hotkeyStyle.Render("d"), helpTextStyle.Render("diff"),
+				hotkeyStyle.Render("R"), helpTextStyle.Render("review"),
+				hotkeyStyle.Render("M"), helpTextStyle.Render("merge"),
+				hotkeyStyle.Render("A"), helpTextStyle.Render("auto-merge"),
+				hotkeyStyle.Render("P"), helpTextStyle.Render("pending"),
+				hotkeyStyle.Render("O"), helpTextStyle.Render("failing check"),
+				hotkeyStyle.Render("e"), helpTextStyle.Render("expand"),
+				hotkeyStyle.Render("W"), helpTextStyle.Render("reviewers"),
+				hotkeyStyle.Render("U"), helpTextStyle.Render("unrequest me"),
+			)
+		}
 	}
 	if m.commentMode {
 		help = fmt.Sprintf(
-			"%s %s  %s %s",
+			"%s %s  %s %s  %s %s  %s %s  %s %s",
 			hotkeyStyle.Render("ctrl+g"), helpTextStyle.Render("send"),
+			hotkeyStyle.Render("ctrl+o"), helpTextStyle.Render("$EDITOR"),
+			hotkeyStyle.Render("ctrl+r/s"), helpTextStyle.Render("insert/save reply"),
+			hotkeyStyle.Render("ctrl+y"), helpTextStyle.Render("preview"),
 			hotkeyStyle.Render("esc"), helpTextStyle.Render("cancel"),
 		)
+		if m.completion != nil && len(m.completion.items) > 0 {
+			help = fmt.Sprintf(
+				"%s %s  %s %s  %s %s",
+				hotkeyStyle.Render("tab"), helpTextStyle.Render("complete"),
+				hotkeyStyle.Render("ctrl+n/p"), helpTextStyle.Render("choose"),
+				hotkeyStyle.Render("esc"), helpTextStyle.Render("dismiss"),
+			)
+		}
+		switch m.composeKind {
+		case composeReview:
+			help += fmt.Sprintf("  %s %s",
+				hotkeyStyle.Render("ctrl+t"), helpTextStyle.Render("approve/request changes/comment"),
+			)
+		case composeClose:
+			if m.closeReason != "" {
+				help += fmt.Sprintf("  %s %s",
+					hotkeyStyle.Render("ctrl+t"), helpTextStyle.Render("reason"),
+				)
+			}
+		case composeMerge:
+			help += fmt.Sprintf("  %s %s",
+				hotkeyStyle.Render("ctrl+t"), helpTextStyle.Render("merge/squash/rebase"),
+			)
+			if !m.mergeAuto {
+				help += fmt.Sprintf("  %s %s",
+					hotkeyStyle.Render("ctrl+b"), helpTextStyle.Render("delete branch"),
+				)
+			}
+		}
 	}
 	if m.confirmMode {
 		help = fmt.Sprintf(
@@ -389,13 +1190,58 @@ func (m model) View() string {
 	if strings.HasPrefix(m.status, "Error:") {
 		status = m.styles.StatusErr.Render(m.status)
 	}
-	if m.loading || m.detailLoading || m.actionLoading {
+	if m.diffMode && !m.pendingMode && !m.commentMode && !m.confirmMode {
+		help = fmt.Sprintf(
+			"%s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
+			hotkeyStyle.Render("esc"), helpTextStyle.Render("back"),
+			hotkeyStyle.Render("n/p"), helpTextStyle.Render("file"),
+			hotkeyStyle.Render("]/["), helpTextStyle.Render("hunk"),
+			hotkeyStyle.Render("j/k g/G"), helpTextStyle.Render("move"),
+			hotkeyStyle.Render("v"), helpTextStyle.Render("range"),
+			hotkeyStyle.Render("c"), helpTextStyle.Render("comment"),
+			hotkeyStyle.Render("s"), helpTextStyle.Render("suggest"),
+			hotkeyStyle.Render("P"), helpTextStyle.Render("pending"),
+			hotkeyStyle.Render("R"), helpTextStyle.Render("review"),
+			hotkeyStyle.Render("o"), helpTextStyle.Render("open files"),
+			hotkeyStyle.Render("q"), helpTextStyle.Render("quit"),
+		)
+	}
+	if m.pendingMode && !m.commentMode && !m.confirmMode {
+		help = fmt.Sprintf(
+			"%s %s  %s %s  %s %s  %s %s  %s %s  %s %s",
+			hotkeyStyle.Render("esc"), helpTextStyle.Render("back"),
+			hotkeyStyle.Render("j/k"), helpTextStyle.Render("select"),
+			hotkeyStyle.Render("e"), helpTextStyle.Render("edit"),
+			hotkeyStyle.Render("x"), helpTextStyle.Render("delete"),
+			hotkeyStyle.Render("R"), helpTextStyle.Render("submit review"),
+			hotkeyStyle.Render("D"), helpTextStyle.Render("discard all"),
+		)
+	}
+	if m.pickerMode {
+		help = fmt.Sprintf(
+			"%s %s  %s %s  %s %s",
+			hotkeyStyle.Render("↑/↓"), helpTextStyle.Render("move"),
+			hotkeyStyle.Render("enter"), helpTextStyle.Render("apply"),
+			hotkeyStyle.Render("esc"), helpTextStyle.Render("cancel"),
+		)
+		if m.picker.multi {
+			help += fmt.Sprintf("  %s %s",
+				hotkeyStyle.Render("tab"), helpTextStyle.Render("toggle"),
+			)
+		}
+		if m.pickerKind == pickDrafts {
+			help += fmt.Sprintf("  %s %s",
+				hotkeyStyle.Render("ctrl+x"), helpTextStyle.Render("delete draft"),
+			)
+		}
+	}
+	if m.loading || m.detailLoading || m.actionLoading || m.diffLoading {
 		status = fmt.Sprintf("%s %s", m.spinner.View(), status)
 	}
 	if !m.statusOverride && !m.lastUpdated.IsZero() && !m.loading && m.err == nil {
-		status = m.styles.Status.Render(fmt.Sprintf("Loaded %d items • updated %s", len(m.list.Items()), humanizeSince(m.lastUpdated)))
+		status = m.styles.Status.Render(fmt.Sprintf("Loaded %d items • updated %s", len(m.items), humanizeSince(m.lastUpdated)))
 	}
-	footer := fmt.Sprintf("%s\n%s", help, status)
+	footer := fmt.Sprintf("%s\n%s", ansi.Truncate(help, m.width, "…"), status)
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		title,
@@ -405,8 +1251,12 @@ func (m model) View() string {
 	)
 }
 
-func (m model) detailView() string {
-	if m.detailLoading {
+func (m model) detailVisible() bool {
+	return m.showDetail || m.splitActive()
+}
+
+func (m model) detailView(width int) string {
+	if m.detailLoading && m.detailItem.URL != m.detailTarget.URL {
 		return fmt.Sprintf("%s %s", m.spinner.View(), m.styles.Status.Render("Loading details..."))
 	}
 	if m.detailErr != nil {
@@ -416,6 +1266,10 @@ func (m model) detailView() string {
 		return m.styles.MutedText.Render("No details loaded.")
 	}
 
+	return fmt.Sprintf("%s\n%s\n%s", m.detailHeader(width), m.viewport.View(), m.scrollLine(width))
+}
+
+func (m model) detailHeader(width int) string {
 	info := fmt.Sprintf(
 		"%s • %s • #%d • %s • %d comments • updated %s",
 		m.detailItem.Repo,
@@ -433,53 +1287,182 @@ func (m model) detailView() string {
 			draft = "draft"
 		}
 		mergeable := formatMergeable(m.detailItem.Mergeable)
+		if state := m.detailItem.MergeableState; state != "" && state != "unknown" && state != "clean" {
+			mergeable += " (" + state + ")"
+		}
 		reviews := fmt.Sprintf("reviews: +%d / -%d / %d",
 			m.detailItem.ReviewApprovals,
 			m.detailItem.ReviewChanges,
 			m.detailItem.ReviewComments,
 		)
-		extra = fmt.Sprintf("state: %s • mergeable: %s • %s • +%d/-%d • files %d • commits %d",
+		extra = fmt.Sprintf("state: %s • mergeable: %s • auto-merge: %s • %s • +%d/-%d • files %d • commits %d",
 			draft,
 			mergeable,
+			formatAutoMerge(m.detailItem.AutoMerge),
 			reviews,
 			m.detailItem.Additions,
 			m.detailItem.Deletions,
 			m.detailItem.ChangedFiles,
 			m.detailItem.Commits,
 		)
+		extra += fmt.Sprintf("\nreviewers: %s • assignees: %s",
+			formatReviewers(m.detailItem.Reviewers, m.detailItem.Repo),
+			formatList(m.detailItem.Assignees),
+		)
 	case "Issue":
-		extra = fmt.Sprintf("labels: %s • assignees: %s",
-			formatList(m.detailItem.Labels),
+		extra = fmt.Sprintf("assignees: %s",
 			formatList(m.detailItem.Assignees),
 		)
 	}
 
-	titleStyle := m.styles.AccentText
-	bodyStyle := m.styles.BodyText.Copy().Width(m.width - 2)
-	comments := renderComments(m.detailItem.CommentList, m.detailItem.CommentPage, m.detailItem.HasNextComments, m.detailItem.HasPrevComments, m.width-2, m.styles)
-	metaLine := m.styles.MetaText.Render(info)
+	titleStyle := m.styles.AccentText.Copy().Width(width)
+	metaLine := m.styles.MetaText.Copy().Width(width).Render(info)
 	if extra != "" {
-		metaLine = metaLine + "\n" + m.styles.MutedText.Render(extra)
+		metaLine = metaLine + "\n" + m.styles.MutedText.Copy().Width(width).Render(extra)
 	}
-	content := fmt.Sprintf("%s\n%s\n\n%s\n\n%s",
-		titleStyle.Render(m.detailItem.Title),
-		metaLine,
-		bodyStyle.Render(strings.TrimSpace(m.detailItem.Body)),
-		comments,
-	)
+	if len(m.detailItem.Labels) > 0 {
+		metaLine = metaLine + "\n" + ansi.Truncate(renderLabelChips(m.detailItem.Labels), width, "…")
+	}
+	return fmt.Sprintf("%s\n%s\n", titleStyle.Render(m.detailItem.Title), metaLine)
+}
 
+func (m model) detailBody(width int) string {
+	content, _ := m.detailContent(width)
 	return content
 }
 
+// detailContent renders the scrollable part of the detail view and reports
+// the line at which the selected review thread starts (-1 if none).
+func (m model) detailContent(width int) (string, int) {
+	body := strings.TrimSpace(m.detailItem.Body)
+	if body == "" {
+		body = m.styles.MutedText.Render("(no description)")
+	} else {
+		body = renderMarkdown(body, width, m.styles)
+	}
+	checks := ""
+	threads := ""
+	anchor := -1
+	if m.detailItem.Kind == "PR" {
+		checks = renderChecks(m.detailItem.Checks, m.detailItem.ChecksError, width, m.styles) + "\n\n"
+		rendered, line := renderThreads(m.detailItem.Threads, m.detailItem.ThreadsError, m.selectedThread, m.expandedThreads, width, m.styles)
+		if line >= 0 {
+			anchor = strings.Count(body+"\n\n"+checks, "\n") + line
+		}
+		threads = rendered + "\n\n"
+	}
+	comments, line := renderComments(m.detailItem.CommentList, m.detailItem.CommentPage, m.detailItem.HasNextComments, m.detailItem.HasPrevComments, m.selectedComment, width, m.styles)
+	if line >= 0 {
+		anchor = strings.Count(body+"\n\n"+checks+threads, "\n") + line
+	}
+	if m.detailSection == sectionTimeline {
+		comments = m.timelineView(width)
+	}
+	return fmt.Sprintf("%s\n\n%s%s%s",
+		body,
+		checks,
+		threads,
+		comments,
+	), anchor
+}
+
+func (m model) selectionAnchor() int {
+	_, anchor := m.detailContent(m.detailPaneWidth())
+	return anchor
+}
+
+func (m model) timelineView(width int) string {
+	switch {
+	case m.timelineLoading && m.timelinePage.Page == 0:
+		return fmt.Sprintf("%s\n  %s", m.styles.AccentText.Render("Timeline"), m.styles.MutedText.Render("Loading timeline..."))
+	case m.timelineErr != nil:
+		return fmt.Sprintf("%s\n  %s", m.styles.AccentText.Render("Timeline"), m.styles.StatusErr.Render("Error loading timeline: "+m.timelineErr.Error()))
+	}
+	return renderTimeline(m.timeline, m.timelinePage.Page, m.timelinePage.HasNext, m.timelinePage.HasPrev, width, m.styles)
+}
+
 func (m model) commentView() string {
 	title := m.styles.AccentText.Render("New Comment")
 	info := m.styles.MetaText.Render(fmt.Sprintf("%s • #%d", m.actionItem.Repo, m.actionItem.Number))
-	return fmt.Sprintf("%s\n%s\n\n%s", title, info, m.textarea.View())
+	switch m.composeKind {
+	case composeEditComment:
+		title = m.styles.AccentText.Render("Edit Comment")
+	case composeClose:
+		title = m.styles.AccentText.Render("Close " + strings.ToLower(m.actionItem.Kind))
+		if m.closeReason != "" {
+			title = m.styles.AccentText.Render("Close as " + closeReasonLabel(m.closeReason))
+		}
+		info = m.styles.MetaText.Render(fmt.Sprintf("%s • #%d • optional closing comment", m.actionItem.Repo, m.actionItem.Number))
+	case composeIssueField, composeNewIssue:
+		name, details := m.issueComposerTitle()
+		title = m.styles.AccentText.Render(name)
+		info = m.styles.MetaText.Render(details)
+	case composeQuoteReply:
+		if i := commentIndex(m.detailItem.CommentList, m.editingComment); i >= 0 {
+			title = m.styles.AccentText.Render("Reply to " + m.detailItem.CommentList[i].Author)
+		}
+	case composeThreadReply:
+		title = m.styles.AccentText.Render("Reply to Thread")
+		info = m.styles.MetaText.Render(fmt.Sprintf("%s • #%d • %s", m.actionItem.Repo, m.actionItem.Number, m.replyThread.location()))
+	case composeReview:
+		title = m.styles.AccentText.Render("Review: " + reviewEventLabel(m.reviewEvent))
+		if pending := len(m.pendingReviews[m.actionItem.URL]); pending > 0 {
+			info = m.styles.MetaText.Render(fmt.Sprintf("%s • #%d • %d pending line comments", m.actionItem.Repo, m.actionItem.Number, pending))
+		}
+	case composeMerge:
+		title = m.styles.AccentText.Render("Merge: " + m.mergeMethod)
+		if m.mergeAuto {
+			title = m.styles.AccentText.Render("Auto-merge: " + m.mergeMethod)
+		}
+		info = m.mergeComposerInfo()
+	case composeLineComment:
+		location := m.draftComment.location()
+		if comments := m.pendingReviews[m.detailTarget.URL]; m.editingPending >= 0 && m.editingPending < len(comments) {
+			location = comments[m.editingPending].location()
+		}
+		title = m.styles.AccentText.Render("Line Comment")
+		info = m.styles.MetaText.Render(fmt.Sprintf("%s • #%d • %s", m.actionItem.Repo, m.actionItem.Number, location))
+	}
+	body := m.composerBody()
+	if popup := m.completionView(m.width - 4); popup != "" {
+		body += "\n" + popup
+	}
+	return fmt.Sprintf("%s\n%s\n\n%s", title, info, body)
 }
 
 func (m model) confirmView() string {
-	actionText := actionLabel(m.confirmTargetState)
 	target := fmt.Sprintf("%s • #%d", m.actionItem.Repo, m.actionItem.Number)
+	if m.confirmAction == confirmMerge {
+		return m.mergeConfirmView()
+	}
+	if m.confirmAction == confirmDisableAutoMerge {
+		prompt := fmt.Sprintf("Disable auto-merge (%s)?", formatAutoMerge(m.detailItem.AutoMerge))
+		return m.styles.Confirm.Render(fmt.Sprintf("%s\n%s", prompt, target))
+	}
+	if m.confirmAction == confirmDeleteComment {
+		prompt := "Delete this comment?"
+		if i := commentIndex(m.detailItem.CommentList, m.editingComment); i >= 0 {
+			first, _, _ := strings.Cut(strings.TrimSpace(m.detailItem.CommentList[i].Body), "\n")
+			prompt = fmt.Sprintf("Delete this comment?\n%s", ansi.Truncate(first, max(10, m.width-8), "…"))
+		}
+		return m.styles.Confirm.Render(fmt.Sprintf("%s\n%s", prompt, target))
+	}
+	if m.confirmAction == confirmDiscardReview {
+		prompt := fmt.Sprintf("Discard %d pending review comments?", len(m.pendingReviews[m.detailTarget.URL]))
+		return m.styles.Confirm.Render(fmt.Sprintf("%s\n%s", prompt, target))
+	}
+	if m.confirmAction == confirmReview {
+		prompt := fmt.Sprintf("Submit review: %s?", reviewEventLabel(m.reviewEvent))
+		if pending := len(m.pendingReviews[m.actionItem.URL]); pending > 0 {
+			prompt = fmt.Sprintf("Submit review: %s with %d line comments?", reviewEventLabel(m.reviewEvent), pending)
+		}
+		body := m.pendingBody
+		if body == "" {
+			body = "(no body)"
+		}
+		return m.styles.Confirm.Render(fmt.Sprintf("%s\n%s\n\n%s", prompt, target, ansi.Truncate(strings.SplitN(body, "\n", 2)[0], max(20, m.width-10), "…")))
+	}
+	actionText := actionLabel(m.confirmTargetState)
 	prompt := fmt.Sprintf("%s this %s?", actionText, strings.ToLower(m.actionItem.Kind))
 	content := fmt.Sprintf("%s\n%s", prompt, target)
 	return m.styles.Confirm.Render(content)
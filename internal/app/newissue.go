@@ -0,0 +1,814 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	recentReposFileName = "recent_repos.json"
+	maxRecentRepos      = 10
+	maxIssueTemplates   = 20
+	maxTemplateBytes    = 256 << 10
+	blankTemplate       = "blank"
+)
+
+// issueTemplate is a markdown template or a YAML issue form from
+// .github/ISSUE_TEMPLATE. Forms have Fields; markdown templates a Body.
+type issueTemplate struct {
+	File      string
+	Name      string
+	About     string
+	Title     string
+	Labels    []string
+	Assignees []string
+	Body      string
+	Fields    []issueFormField
+}
+
+// issueFormField is one element of an issue form's body.
+type issueFormField struct {
+	Type        string
+	Label       string
+	Description string
+	Placeholder string
+	Value       string
+	Render      string
+	Options     []string
+	Multiple    bool
+	Required    bool
+	// RequiredOptions lists the checkboxes that must be ticked.
+	RequiredOptions []string
+}
+
+// templateFile is an issue form, or the front matter of a markdown
+// template, which has about instead of description and no body.
+type templateFile struct {
+	Name        string      `yaml:"name"`
+	About       string      `yaml:"about"`
+	Description string      `yaml:"description"`
+	Title       string      `yaml:"title"`
+	Labels      stringList  `yaml:"labels"`
+	Assignees   stringList  `yaml:"assignees"`
+	Body        []formEntry `yaml:"body"`
+}
+
+type formEntry struct {
+	Type       string `yaml:"type"`
+	Attributes struct {
+		Label       string       `yaml:"label"`
+		Description string       `yaml:"description"`
+		Placeholder string       `yaml:"placeholder"`
+		Value       string       `yaml:"value"`
+		Render      string       `yaml:"render"`
+		Multiple    bool         `yaml:"multiple"`
+		Options     []formOption `yaml:"options"`
+	} `yaml:"attributes"`
+	Validations struct {
+		Required bool `yaml:"required"`
+	} `yaml:"validations"`
+}
+
+// formOption is a dropdown option, written as a plain string, or a
+// checkbox with a label and whether it has to be ticked.
+type formOption struct {
+	Label    string `yaml:"label"`
+	Required bool   `yaml:"required"`
+}
+
+func (o *formOption) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		o.Label = node.Value
+		return nil
+	}
+	type plain formOption
+	return node.Decode((*plain)(o))
+}
+
+// stringList accepts a list of strings or a single comma-separated
+// string, the two forms templates use for labels and assignees.
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	var values []string
+	if node.Kind == yaml.ScalarNode {
+		values = strings.Split(node.Value, ",")
+	} else if err := node.Decode(&values); err != nil {
+		return err
+	}
+	*l = nil
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// newIssueForm tracks an issue being written: the template, the form field
+// being prompted for and the answers so far.
+type newIssueForm struct {
+	repo     string
+	template issueTemplate
+	field    int
+	answers  [][]string
+}
+
+type issueTemplatesResult struct {
+	repo      string
+	templates []issueTemplate
+	skipped   []string
+	blank     bool
+	err       error
+}
+
+type issueCreatedResult struct {
+	item issueItem
+	err  error
+}
+
+type recentReposSavedResult struct {
+	err error
+}
+
+func recentReposFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "github_inbox_tui", recentReposFileName), nil
+}
+
+// loadRecentRepos reads the repositories recently opened or filed in. Like
+// drafts, a missing or broken file just means no history.
+func loadRecentRepos() []string {
+	path, err := recentReposFilePath()
+	if err != nil {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var repos []string
+	if err := json.Unmarshal(raw, &repos); err != nil {
+		return nil
+	}
+	return repos
+}
+
+// noteRecentRepo moves repo to the front of the history and persists it
+// when the order changed.
+func (m *model) noteRecentRepo(repo string) tea.Cmd {
+	if repo == "" || (len(m.recentRepos) > 0 && m.recentRepos[0] == repo) {
+		return nil
+	}
+	repos := []string{repo}
+	for _, r := range m.recentRepos {
+		if r != repo && len(repos) < maxRecentRepos {
+			repos = append(repos, r)
+		}
+	}
+	m.recentRepos = repos
+	raw, err := json.MarshalIndent(repos, "", "  ")
+	return func() tea.Msg {
+		if err != nil {
+			return recentReposSavedResult{err: err}
+		}
+		path, err := recentReposFilePath()
+		if err != nil {
+			return recentReposSavedResult{err: err}
+		}
+		return recentReposSavedResult{err: writeFileAtomic(path, raw)}
+	}
+}
+
+// fetchRepoFile returns the raw content of a file in the default branch.
+func fetchRepoFile(ctx context.Context, token, repo, filePath string) (string, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s", repo, filePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", err
+	}
+	addJSONHeaders(req, token)
+	req.Header.Set("Accept", "application/vnd.github.raw+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", readAPIError(resp)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateBytes))
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// fetchIssueTemplates lists .github/ISSUE_TEMPLATE and parses every
+// markdown template and issue form in it. Templates that do not parse are
+// skipped and named, with the reason, in skipped. It also reports whether
+// blank issues are allowed, per the folder's config.yml.
+func fetchIssueTemplates(ctx context.Context, token, repo string) (templates []issueTemplate, skipped []string, blank bool, err error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/.github/ISSUE_TEMPLATE", repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, true, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, true, readAPIError(resp)
+	}
+	var entries []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		// A file named ISSUE_TEMPLATE decodes as an object, not a listing.
+		return nil, nil, true, nil
+	}
+
+	blank = true
+	for _, e := range entries {
+		if e.Type != "file" {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(e.Name))
+		if ext != ".md" && ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		if len(templates) >= maxIssueTemplates {
+			break
+		}
+		content, err := fetchRepoFile(ctx, token, repo, e.Path)
+		if err != nil {
+			return nil, nil, true, err
+		}
+		if strings.TrimSuffix(strings.ToLower(e.Name), ext) == "config" {
+			blank = blankIssuesEnabled(content)
+			continue
+		}
+		var t issueTemplate
+		if ext == ".md" {
+			t, err = parseMarkdownTemplate(content)
+		} else {
+			t, err = parseIssueForm(content)
+		}
+		if err != nil {
+			skipped = append(skipped, sanitizeLine(fmt.Sprintf("%s: %s", e.Name, err.Error())))
+			continue
+		}
+		t.File = sanitizeLine(e.Name)
+		if t.Name == "" {
+			t.Name = t.File
+		}
+		templates = append(templates, t)
+	}
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].File < templates[j].File
+	})
+	return templates, skipped, blank, nil
+}
+
+// blankIssuesEnabled reads blank_issues_enabled from the template chooser's
+// config.yml. Blank issues stay allowed when it is missing or unreadable.
+func blankIssuesEnabled(config string) bool {
+	var c struct {
+		BlankIssuesEnabled *bool `yaml:"blank_issues_enabled"`
+	}
+	if err := yaml.Unmarshal([]byte(config), &c); err != nil || c.BlankIssuesEnabled == nil {
+		return true
+	}
+	return *c.BlankIssuesEnabled
+}
+
+// parseMarkdownTemplate splits a markdown template into its YAML front
+// matter and body.
+func parseMarkdownTemplate(content string) (issueTemplate, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var t issueTemplate
+	body := content
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		end := strings.Index(rest, "\n---")
+		if end < 0 {
+			return t, errors.New("unterminated front matter")
+		}
+		var front templateFile
+		if err := yaml.Unmarshal([]byte(rest[:end]), &front); err != nil {
+			return t, err
+		}
+		t.applyFrontMatter(front)
+		t.About = sanitizeLine(front.About)
+		body = rest[end+len("\n---"):]
+		_, body, _ = strings.Cut(body, "\n")
+	}
+	t.Body = sanitizeText(strings.TrimSpace(body))
+	return t, nil
+}
+
+// parseIssueForm reads a YAML issue form.
+func parseIssueForm(content string) (issueTemplate, error) {
+	var t issueTemplate
+	var file templateFile
+	if err := yaml.Unmarshal([]byte(content), &file); err != nil {
+		return t, err
+	}
+	t.applyFrontMatter(file)
+	t.About = sanitizeLine(file.Description)
+	for _, e := range file.Body {
+		attrs := e.Attributes
+		f := issueFormField{
+			Type:        e.Type,
+			Label:       sanitizeLine(attrs.Label),
+			Description: sanitizeText(strings.TrimSpace(attrs.Description)),
+			Placeholder: sanitizeText(strings.TrimSpace(attrs.Placeholder)),
+			Value:       sanitizeText(strings.TrimSpace(attrs.Value)),
+			Render:      sanitizeLine(attrs.Render),
+			Multiple:    attrs.Multiple,
+			Required:    e.Validations.Required,
+		}
+		for _, option := range attrs.Options {
+			label := sanitizeLine(option.Label)
+			if option.Required {
+				f.RequiredOptions = append(f.RequiredOptions, label)
+			}
+			f.Options = append(f.Options, label)
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	if len(t.Fields) == 0 {
+		return t, errors.New("issue form has no body")
+	}
+	return t, nil
+}
+
+func (t *issueTemplate) applyFrontMatter(front templateFile) {
+	t.Name = sanitizeLine(front.Name)
+	t.Title = sanitizeLine(front.Title)
+	for _, label := range front.Labels {
+		t.Labels = append(t.Labels, sanitizeLine(label))
+	}
+	for _, login := range front.Assignees {
+		t.Assignees = append(t.Assignees, sanitizeLine(strings.TrimPrefix(login, "@")))
+	}
+}
+
+// prompted reports whether the field asks for input; markdown elements
+// only explain the form and are not part of the issue.
+func (f issueFormField) prompted() bool {
+	switch f.Type {
+	case "input", "textarea", "dropdown", "checkboxes":
+		return true
+	}
+	return false
+}
+
+// formBody lays out form answers the way GitHub does: a heading per field
+// followed by the answer, or "_No response_".
+func formBody(fields []issueFormField, answers [][]string) string {
+	var sections []string
+	for i, f := range fields {
+		if !f.prompted() {
+			continue
+		}
+		var values []string
+		if i < len(answers) {
+			values = answers[i]
+		}
+		answer := "_No response_"
+		switch {
+		case f.Type == "checkboxes":
+			checked := make(map[string]bool)
+			for _, v := range values {
+				checked[v] = true
+			}
+			lines := make([]string, 0, len(f.Options))
+			for _, option := range f.Options {
+				box := "- [ ] "
+				if checked[option] {
+					box = "- [x] "
+				}
+				lines = append(lines, box+option)
+			}
+			answer = strings.Join(lines, "\n")
+		case len(values) > 0 && strings.TrimSpace(strings.Join(values, "")) != "":
+			answer = strings.Join(values, ", ")
+			if f.Render != "" {
+				answer = "```" + f.Render + "\n" + answer + "\n```"
+			}
+		}
+		sections = append(sections, "### "+f.Label+"\n\n"+answer)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// createIssue files a new issue and returns it as a list item.
+func createIssue(ctx context.Context, token, repo, title, body string, labels, assignees []string) (issueItem, error) {
+	payload := map[string]any{"title": title, "body": body}
+	if len(labels) > 0 {
+		payload["labels"] = labels
+	}
+	if len(assignees) > 0 {
+		payload["assignees"] = assignees
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return issueItem{}, err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues", repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return issueItem{}, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return issueItem{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return issueItem{}, readAPIError(resp)
+	}
+	var issue struct {
+		Number    int       `json:"number"`
+		Title     string    `json:"title"`
+		HTMLURL   string    `json:"html_url"`
+		State     string    `json:"state"`
+		UpdatedAt time.Time `json:"updated_at"`
+		User      struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return issueItem{}, err
+	}
+	return issueItem{
+		TitleText: sanitizeLine(issue.Title),
+		Repo:      repo,
+		Number:    issue.Number,
+		URL:       issue.HTMLURL,
+		Kind:      "Issue",
+		State:     issue.State,
+		Author:    sanitizeLine(issue.User.Login),
+		Updated:   issue.UpdatedAt,
+	}, nil
+}
+
+func fetchIssueTemplatesCmd(repo string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return issueTemplatesResult{repo: repo, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		templates, skipped, blank, err := fetchIssueTemplates(ctx, token, repo)
+		return issueTemplatesResult{repo: repo, templates: templates, skipped: skipped, blank: blank, err: err}
+	}
+}
+
+func createIssueCmd(repo, title, body string, labels, assignees []string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return issueCreatedResult{err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		item, err := createIssue(ctx, token, repo, title, body, labels, assignees)
+		return issueCreatedResult{item: item, err: err}
+	}
+}
+
+// openRepoPicker starts a new issue by choosing the repository: recent
+// ones first, then those in the list. Any owner/name can be typed.
+func (m *model) openRepoPicker() {
+	seen := make(map[string]bool)
+	var options []pickerOption
+	for _, repo := range m.recentRepos {
+		if !seen[repo] {
+			seen[repo] = true
+			options = append(options, pickerOption{Value: repo, Label: repo, Hint: "recent"})
+		}
+	}
+	for _, item := range m.items {
+		if !seen[item.Repo] {
+			seen[item.Repo] = true
+			options = append(options, pickerOption{Value: item.Repo, Label: item.Repo})
+		}
+	}
+	m.picker = newPicker("New issue in", options, nil, false)
+	m.picker.freeText = true
+	m.picker.input.Placeholder = "owner/name or search..."
+	m.pickerKind = pickIssueRepo
+	m.pickerMode = true
+}
+
+// submitRepo loads the templates of the chosen repository. A typed
+// owner/name wins over the highlighted option.
+func (m *model) submitRepo() tea.Cmd {
+	repo := m.picker.Query()
+	if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		option, ok := m.picker.current()
+		if !ok {
+			m.picker.errorMsg = "Enter a repository as owner/name"
+			m.pickerMode = true
+			return nil
+		}
+		repo = option.Value
+	}
+	if templates, ok := m.issueTemplates[repo]; ok {
+		return m.openTemplatePicker(repo, templates, m.blankIssues[repo])
+	}
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Loading issue templates for %s...", repo)
+	m.statusOverride = true
+	return fetchIssueTemplatesCmd(repo)
+}
+
+func (m *model) handleIssueTemplates(msg issueTemplatesResult) tea.Cmd {
+	m.actionLoading = false
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Error: issue templates: %s", msg.err.Error())
+		m.statusOverride = true
+		return nil
+	}
+	if m.issueTemplates == nil {
+		m.issueTemplates = make(map[string][]issueTemplate)
+		m.blankIssues = make(map[string]bool)
+	}
+	m.issueTemplates[msg.repo] = msg.templates
+	m.blankIssues[msg.repo] = msg.blank
+	m.status = ""
+	m.statusOverride = false
+	if len(msg.skipped) > 0 {
+		m.status = fmt.Sprintf("Skipped unreadable templates: %s", strings.Join(msg.skipped, "; "))
+		m.statusOverride = true
+	}
+	return m.openTemplatePicker(msg.repo, msg.templates, msg.blank)
+}
+
+// openTemplatePicker offers the repository's templates, plus a blank
+// issue unless config.yml disables it. Without templates the composer
+// opens straight away.
+func (m *model) openTemplatePicker(repo string, templates []issueTemplate, blank bool) tea.Cmd {
+	if len(templates) == 0 {
+		return m.startNewIssue(repo, issueTemplate{})
+	}
+	options := make([]pickerOption, 0, len(templates)+1)
+	for i, t := range templates {
+		options = append(options, pickerOption{Value: strconv.Itoa(i), Label: t.Name, Hint: t.About})
+	}
+	if blank {
+		options = append(options, pickerOption{Value: blankTemplate, Label: "Blank issue", Hint: "start from scratch"})
+	}
+	m.picker = newPicker("Template for "+repo, options, nil, false)
+	m.pickerKind = pickIssueTemplate
+	m.pickerMode = true
+	m.newIssue = &newIssueForm{repo: repo}
+	return nil
+}
+
+func (m *model) submitTemplate(value string) tea.Cmd {
+	repo := m.newIssue.repo
+	if value == blankTemplate {
+		return m.startNewIssue(repo, issueTemplate{})
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 || i >= len(m.issueTemplates[repo]) {
+		return nil
+	}
+	return m.startNewIssue(repo, m.issueTemplates[repo][i])
+}
+
+func (m *model) startNewIssue(repo string, t issueTemplate) tea.Cmd {
+	m.newIssue = &newIssueForm{repo: repo, template: t, answers: make([][]string, len(t.Fields))}
+	m.actionItem = issueItem{Repo: repo, Kind: "Issue"}
+	return m.promptIssueField()
+}
+
+// promptIssueField asks for the next form field: text fields in the
+// composer, dropdowns and checkboxes in a picker. After the last field,
+// or straight away for markdown templates, the composer shows the whole
+// issue with the title on its first line.
+func (m *model) promptIssueField() tea.Cmd {
+	form := m.newIssue
+	for form.field < len(form.template.Fields) && !form.template.Fields[form.field].prompted() {
+		form.field++
+	}
+	if form.field >= len(form.template.Fields) {
+		body := form.template.Body
+		if len(form.template.Fields) > 0 {
+			body = formBody(form.template.Fields, form.answers)
+		}
+		m.openIssueComposer(composeNewIssue, form.template.Title+"\n\n"+body)
+		// Start on the title line, after any prefix the template gives.
+		for m.textarea.Line() > 0 {
+			m.textarea.CursorUp()
+		}
+		m.textarea.CursorEnd()
+		return nil
+	}
+
+	f := form.template.Fields[form.field]
+	switch f.Type {
+	case "dropdown", "checkboxes":
+		options := make([]pickerOption, 0, len(f.Options))
+		for _, option := range f.Options {
+			hint := ""
+			for _, required := range f.RequiredOptions {
+				if required == option {
+					hint = "required"
+				}
+			}
+			options = append(options, pickerOption{Value: option, Label: option, Hint: hint})
+		}
+		multi := f.Multiple || f.Type == "checkboxes"
+		m.picker = newPicker(f.Label, options, form.answers[form.field], multi)
+		m.pickerKind = pickIssueField
+		m.pickerMode = true
+	default:
+		value := f.Value
+		if answer := form.answers[form.field]; len(answer) > 0 {
+			value = answer[0]
+		}
+		m.openIssueComposer(composeIssueField, value)
+	}
+	return nil
+}
+
+func (m *model) openIssueComposer(kind int, text string) {
+	m.commentMode = true
+	m.composeKind = kind
+	m.textarea.Focus()
+	m.textarea.SetValue(text)
+}
+
+// submitIssueField stores the composer's answer to a text field and moves
+// on to the next one.
+func (m *model) submitIssueField(body string) tea.Cmd {
+	form := m.newIssue
+	f := form.template.Fields[form.field]
+	if f.Required && body == "" {
+		m.status = fmt.Sprintf("%q is required", f.Label)
+		m.statusOverride = true
+		return nil
+	}
+	m.commentMode = false
+	m.textarea.Blur()
+	m.textarea.SetValue("")
+	m.status = ""
+	m.statusOverride = false
+	form.answers[form.field] = []string{body}
+	form.field++
+	return m.promptIssueField()
+}
+
+// submitIssueChoice stores the options picked for a dropdown or
+// checkboxes field.
+func (m *model) submitIssueChoice() tea.Cmd {
+	form := m.newIssue
+	f := form.template.Fields[form.field]
+	var values []string
+	if m.picker.multi {
+		values = m.picker.Checked()
+	} else if option, ok := m.picker.current(); ok {
+		values = []string{option.Value}
+	}
+	checked := make(map[string]bool)
+	for _, v := range values {
+		checked[v] = true
+	}
+	for _, required := range f.RequiredOptions {
+		if !checked[required] {
+			m.picker.errorMsg = fmt.Sprintf("%q must be checked", required)
+			m.pickerMode = true
+			return nil
+		}
+	}
+	if f.Required && len(values) == 0 {
+		m.picker.errorMsg = "Pick at least one option"
+		m.pickerMode = true
+		return nil
+	}
+	form.answers[form.field] = values
+	form.field++
+	return m.promptIssueField()
+}
+
+func (m *model) submitNewIssue(text string) tea.Cmd {
+	// Unlike a merge message the title line is not trimmed away first: an
+	// empty one means the title is missing, not that the body holds it.
+	title, body, _ := strings.Cut(text, "\n")
+	title, body = strings.TrimSpace(title), strings.TrimSpace(body)
+	if title == "" {
+		m.status = "Issue title is required"
+		m.statusOverride = true
+		return nil
+	}
+	form := m.newIssue
+	m.commentMode = false
+	m.textarea.Blur()
+	m.pendingBody = text
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Creating issue in %s...", form.repo)
+	m.statusOverride = true
+	return createIssueCmd(form.repo, title, body, form.template.Labels, form.template.Assignees)
+}
+
+// handleIssueCreated opens the new issue in the detail view. A failed post
+// reopens the composer with the text intact.
+func (m *model) handleIssueCreated(msg issueCreatedResult) tea.Cmd {
+	m.actionLoading = false
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Error: %s%s", msg.err.Error(), m.settleEditorFile(msg.err))
+		m.statusOverride = true
+		if m.newIssue != nil {
+			m.openIssueComposer(composeNewIssue, m.pendingBody)
+		}
+		return nil
+	}
+	m.settleEditorFile(nil)
+	m.newIssue = nil
+	m.textarea.SetValue("")
+	m.status = fmt.Sprintf("Created %s#%d", msg.item.Repo, msg.item.Number)
+	m.statusOverride = true
+	m.showDetail = true
+	m.resize()
+	m.loading = true
+	return tea.Batch(
+		m.noteRecentRepo(msg.item.Repo),
+		m.loadDetail(msg.item, 1),
+		fetchCmd(m.filters[m.filterIndex], tabs[m.tabIndex].Kind),
+	)
+}
+
+// issueComposerTitle describes the new-issue composer: the field being
+// asked for, or the issue as a whole.
+func (m model) issueComposerTitle() (string, string) {
+	form := m.newIssue
+	if form == nil {
+		return "New Issue", ""
+	}
+	if m.composeKind == composeIssueField && form.field < len(form.template.Fields) {
+		f := form.template.Fields[form.field]
+		title := f.Label
+		if f.Required {
+			title += " *"
+		}
+		var info []string
+		if f.Description != "" {
+			info = append(info, f.Description)
+		}
+		if f.Placeholder != "" {
+			info = append(info, "e.g. "+f.Placeholder)
+		}
+		return title, strings.Join(info, " • ")
+	}
+	title := "New Issue"
+	if form.template.Name != "" {
+		title += ": " + form.template.Name
+	}
+	info := []string{form.repo, "first line is the title"}
+	if len(form.template.Labels) > 0 {
+		info = append(info, "labels: "+strings.Join(form.template.Labels, ", "))
+	}
+	if len(form.template.Assignees) > 0 {
+		info = append(info, "assignees: "+strings.Join(form.template.Assignees, ", "))
+	}
+	return title, strings.Join(info, " • ")
+}
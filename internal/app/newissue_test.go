@@ -0,0 +1,310 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bugReportForm follows GitHub's documented issue form example.
+const bugReportForm = `name: Bug Report
+description: File a bug report.
+title: "[Bug]: "
+labels: ["bug", "triage"]
+projects: ["octo-org/1"]
+assignees:
+  - octocat
+body:
+  - type: markdown
+    attributes:
+      value: |
+        Thanks for taking the time to fill out this bug report!
+  - type: input
+    id: contact
+    attributes:
+      label: Contact Details
+      description: How can we get in touch with you if we need more info?
+      placeholder: ex. email@example.com
+    validations:
+      required: false
+  - type: textarea
+    id: what-happened
+    attributes:
+      label: What happened?
+      description: Also tell us, what did you expect to happen?
+      placeholder: Tell us what you see!
+      value: "A bug happened!"
+    validations:
+      required: true
+  - type: dropdown
+    id: version
+    attributes:
+      label: Version
+      description: What version of our software are you running?
+      options:
+        - 1.0.2 (Default)
+        - 1.0.3 (Edge)
+      default: 0
+    validations:
+      required: true
+  - type: dropdown
+    id: browsers
+    attributes:
+      label: What browsers are you seeing the problem on?
+      multiple: true
+      options:
+        - Firefox
+        - Chrome
+  - type: textarea
+    id: logs
+    attributes:
+      label: Relevant log output
+      description: >-
+        Please copy and paste any relevant log output. This will be
+        automatically formatted into code, so no need for backticks.
+      render: shell
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow this project's Code of Conduct
+          required: true
+        - label: I searched for duplicates
+`
+
+func TestParseIssueForm(t *testing.T) {
+	form, err := parseIssueForm(bugReportForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Name != "Bug Report" || form.About != "File a bug report." || form.Title != "[Bug]: " {
+		t.Errorf("header = %q / %q / %q", form.Name, form.About, form.Title)
+	}
+	if !reflect.DeepEqual(form.Labels, []string{"bug", "triage"}) {
+		t.Errorf("labels = %#v", form.Labels)
+	}
+	if !reflect.DeepEqual(form.Assignees, []string{"octocat"}) {
+		t.Errorf("assignees = %#v", form.Assignees)
+	}
+	if len(form.Fields) != 7 {
+		t.Fatalf("got %d fields, want 7", len(form.Fields))
+	}
+
+	want := []issueFormField{
+		{Type: "markdown", Value: "Thanks for taking the time to fill out this bug report!"},
+		{Type: "input", Label: "Contact Details", Description: "How can we get in touch with you if we need more info?", Placeholder: "ex. email@example.com"},
+		{Type: "textarea", Label: "What happened?", Description: "Also tell us, what did you expect to happen?", Placeholder: "Tell us what you see!", Value: "A bug happened!", Required: true},
+		{Type: "dropdown", Label: "Version", Description: "What version of our software are you running?", Options: []string{"1.0.2 (Default)", "1.0.3 (Edge)"}, Required: true},
+		{Type: "dropdown", Label: "What browsers are you seeing the problem on?", Options: []string{"Firefox", "Chrome"}, Multiple: true},
+		{Type: "textarea", Label: "Relevant log output", Description: "Please copy and paste any relevant log output. This will be automatically formatted into code, so no need for backticks.", Render: "shell"},
+		{Type: "checkboxes", Label: "Code of Conduct", Options: []string{"I agree to follow this project's Code of Conduct", "I searched for duplicates"}, RequiredOptions: []string{"I agree to follow this project's Code of Conduct"}},
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(form.Fields[i], w) {
+			t.Errorf("field %d = %#v\nwant %#v", i, form.Fields[i], w)
+		}
+	}
+}
+
+func TestParseIssueFormErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"not a mapping": "- a\n- b",
+		"no body":       "name: Empty\ndescription: nothing",
+		"bad indent":    "name: x\n   description: y\nbody:\n  - type: input",
+	} {
+		if _, err := parseIssueForm(src); err == nil {
+			t.Errorf("%s: parseIssueForm succeeded", name)
+		}
+	}
+}
+
+// TestParseIssueFormYAML covers YAML a line-based reader gets wrong:
+// multi-line plain scalars, flow mappings and YAML 1.1 booleans.
+func TestParseIssueFormYAML(t *testing.T) {
+	src := `name: Flow
+description: A long
+  description here
+labels: bug, ui
+body:
+  - type: input
+    attributes: {label: Version, placeholder: "1.2.3"}
+    validations: {required: true}
+  - type: dropdown
+    attributes: {label: OS, options: [Linux, macOS], multiple: yes}
+  - type: checkboxes
+    attributes:
+      label: Checks
+      options:
+        - {label: Searched, required: true}
+`
+	form, err := parseIssueForm(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.About != "A long description here" {
+		t.Errorf("description = %q", form.About)
+	}
+	if !reflect.DeepEqual(form.Labels, []string{"bug", "ui"}) {
+		t.Errorf("labels = %#v", form.Labels)
+	}
+	want := []issueFormField{
+		{Type: "input", Label: "Version", Placeholder: "1.2.3", Required: true},
+		{Type: "dropdown", Label: "OS", Options: []string{"Linux", "macOS"}, Multiple: true},
+		{Type: "checkboxes", Label: "Checks", Options: []string{"Searched"}, RequiredOptions: []string{"Searched"}},
+	}
+	if !reflect.DeepEqual(form.Fields, want) {
+		t.Errorf("fields = %#v\nwant %#v", form.Fields, want)
+	}
+}
+
+func TestParseMarkdownTemplate(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want issueTemplate
+	}{
+		{
+			name: "comma-separated labels",
+			src:  "---\nname: Feature request\nabout: Suggest an idea for this project\ntitle: '[FEATURE] '\nlabels: enhancement, needs triage\nassignees: ''\n---\n\n**Is your feature request related to a problem?**\nA clear description.\n",
+			want: issueTemplate{
+				Name:   "Feature request",
+				About:  "Suggest an idea for this project",
+				Title:  "[FEATURE] ",
+				Labels: []string{"enhancement", "needs triage"},
+				Body:   "**Is your feature request related to a problem?**\nA clear description.",
+			},
+		},
+		{
+			name: "list labels and mentions",
+			src:  "---\nname: \"Bug: crash\"\nabout: Report a crash\nlabels:\n  - bug\n  - crash\nassignees: '@octocat, hubot'\n---\nSteps:\n",
+			want: issueTemplate{
+				Name:      "Bug: crash",
+				About:     "Report a crash",
+				Labels:    []string{"bug", "crash"},
+				Assignees: []string{"octocat", "hubot"},
+				Body:      "Steps:",
+			},
+		},
+		{
+			name: "crlf",
+			src:  "---\r\nname: Docs\r\nlabels: [docs]\r\n---\r\nWhat is missing?\r\n",
+			want: issueTemplate{Name: "Docs", Labels: []string{"docs"}, Body: "What is missing?"},
+		},
+		{
+			name: "no front matter",
+			src:  "Describe the problem.\n\n--- \nfooter",
+			want: issueTemplate{Body: "Describe the problem.\n\n--- \nfooter"},
+		},
+		{
+			name: "rule in body",
+			src:  "---\nname: X\n---\nabove\n\n---\n\nbelow",
+			want: issueTemplate{Name: "X", Body: "above\n\n---\n\nbelow"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseMarkdownTemplate(tc.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseMarkdownTemplate = %#v\nwant %#v", got, tc.want)
+			}
+		})
+	}
+	if _, err := parseMarkdownTemplate("---\nname: X\nno end"); err == nil {
+		t.Error("unterminated front matter was accepted")
+	}
+}
+
+func TestBlankIssuesEnabled(t *testing.T) {
+	for src, want := range map[string]bool{
+		"blank_issues_enabled: false\ncontact_links:\n  - name: Forum\n    url: https://example.com\n    about: Ask here": false,
+		"blank_issues_enabled: true": true,
+		"contact_links: []":          true,
+		"":                           true,
+		"blank_issues_enabled: false # no blanks": false,
+		"a: 1\n   b: 2": true,
+	} {
+		if got := blankIssuesEnabled(src); got != want {
+			t.Errorf("blankIssuesEnabled(%q) = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestFormBody(t *testing.T) {
+	form, err := parseIssueForm(bugReportForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answers := [][]string{
+		nil,
+		{""},
+		{"It crashed."},
+		{"1.0.3 (Edge)"},
+		{"Firefox", "Chrome"},
+		{"panic: boom"},
+		{"I agree to follow this project's Code of Conduct"},
+	}
+	want := strings.Join([]string{
+		"### Contact Details\n\n_No response_",
+		"### What happened?\n\nIt crashed.",
+		"### Version\n\n1.0.3 (Edge)",
+		"### What browsers are you seeing the problem on?\n\nFirefox, Chrome",
+		"### Relevant log output\n\n```shell\npanic: boom\n```",
+		"### Code of Conduct\n\n- [x] I agree to follow this project's Code of Conduct\n- [ ] I searched for duplicates",
+	}, "\n\n")
+	if got := formBody(form.Fields, answers); got != want {
+		t.Errorf("formBody =\n%s\nwant\n%s", got, want)
+	}
+
+	empty := formBody(form.Fields, nil)
+	if !strings.Contains(empty, "### Relevant log output\n\n_No response_") {
+		t.Errorf("unanswered render field should have no code block:\n%s", empty)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestFetchIssueTemplatesSkipsBrokenFiles(t *testing.T) {
+	files := map[string]string{
+		"/repos/o/r/contents/.github/ISSUE_TEMPLATE": `[
+			{"name": "bug.yml", "path": ".github/ISSUE_TEMPLATE/bug.yml", "type": "file"},
+			{"name": "broken.yml", "path": ".github/ISSUE_TEMPLATE/broken.yml", "type": "file"},
+			{"name": "config.yml", "path": ".github/ISSUE_TEMPLATE/config.yml", "type": "file"}
+		]`,
+		"/repos/o/r/contents/.github/ISSUE_TEMPLATE/bug.yml":    bugReportForm,
+		"/repos/o/r/contents/.github/ISSUE_TEMPLATE/broken.yml": "name: Broken\nbody: [\n",
+		"/repos/o/r/contents/.github/ISSUE_TEMPLATE/config.yml": "blank_issues_enabled: false",
+	}
+	transport := http.DefaultClient.Transport
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, ok := files[req.URL.Path]
+		status := http.StatusOK
+		if !ok {
+			status = http.StatusNotFound
+		}
+		return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})
+
+	templates, skipped, blank, err := fetchIssueTemplates(context.Background(), "token", "o/r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Name != "Bug Report" {
+		t.Errorf("templates = %#v", templates)
+	}
+	if len(skipped) != 1 || !strings.HasPrefix(skipped[0], "broken.yml: ") {
+		t.Errorf("skipped = %#v", skipped)
+	}
+	if blank {
+		t.Error("config.yml disables blank issues")
+	}
+}
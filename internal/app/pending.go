@@ -0,0 +1,261 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// pendingComment is a line comment waiting in a local pending review until
+// the review is submitted. StartLine is set for multi-line comments.
+type pendingComment struct {
+	Path      string
+	Side      string
+	Line      int
+	StartSide string
+	StartLine int
+	Body      string
+	Context   []string
+}
+
+func (c pendingComment) location() string {
+	if c.StartLine > 0 && c.StartLine != c.Line {
+		return fmt.Sprintf("%s:%d-%d", c.Path, c.StartLine, c.Line)
+	}
+	return fmt.Sprintf("%s:%d", c.Path, c.Line)
+}
+
+// apiPayload is the shape the reviews API expects for a draft comment.
+func (c pendingComment) apiPayload() map[string]any {
+	payload := map[string]any{
+		"path": c.Path,
+		"body": c.Body,
+		"line": c.Line,
+		"side": c.Side,
+	}
+	if c.StartLine > 0 && (c.StartLine != c.Line || c.StartSide != c.Side) {
+		payload["start_line"] = c.StartLine
+		payload["start_side"] = c.StartSide
+	}
+	return payload
+}
+
+// startLineComment opens the composer for the selected diff rows. With
+// suggestion set, the body is prefilled with a suggestion block holding the
+// new-side code of the selection.
+func (m *model) startLineComment(suggestion bool) {
+	if m.diffIndex >= len(m.diffFiles) || len(m.diffRefs) == 0 {
+		return
+	}
+	start, end := m.diffSelection()
+	for start <= end && m.diffRefs[start].Line == 0 {
+		start++
+	}
+	for end >= start && m.diffRefs[end].Line == 0 {
+		end--
+	}
+	if start > end {
+		m.status = "Select a diff line to comment on"
+		m.statusOverride = true
+		return
+	}
+	first, last := m.diffRefs[start], m.diffRefs[end]
+	draft := pendingComment{
+		Path: m.diffFiles[m.diffIndex].Filename,
+		Side: last.Side,
+		Line: last.Line,
+	}
+	if start != end {
+		draft.StartSide = first.Side
+		draft.StartLine = first.Line
+	}
+	var suggested []string
+	for i := start; i <= end; i++ {
+		draft.Context = append(draft.Context, m.diffRefs[i].Raw)
+		if m.diffRefs[i].Side == "RIGHT" && m.diffRefs[i].Line > 0 {
+			suggested = append(suggested, m.diffRefs[i].Raw[1:])
+		}
+	}
+
+	value := ""
+	if suggestion {
+		if last.Side != "RIGHT" {
+			m.status = "Suggestions must end on an added or unchanged line"
+			m.statusOverride = true
+			return
+		}
+		value = "```suggestion\n" + strings.Join(suggested, "\n") + "\n```\n"
+	}
+
+	m.diffSelecting = false
+	m.refreshDiffContent()
+	m.commentMode = true
+	m.composeKind = composeLineComment
+	m.draftComment = draft
+	m.editingPending = -1
+	m.actionItem = m.detailTarget
+	m.textarea.Focus()
+	m.textarea.SetValue(value)
+}
+
+// savePendingComment stores the composed line comment, either as a new
+// entry or over the one being edited.
+func (m *model) savePendingComment(body string) {
+	key := m.detailTarget.URL
+	comments := m.pendingReviews[key]
+	if m.editingPending >= 0 && m.editingPending < len(comments) {
+		comments[m.editingPending].Body = body
+		m.status = fmt.Sprintf("Updated comment on %s", comments[m.editingPending].location())
+	} else {
+		draft := m.draftComment
+		draft.Body = body
+		comments = append(comments, draft)
+		m.status = fmt.Sprintf("Added comment on %s to the pending review (%d)", draft.location(), len(comments))
+	}
+	if m.pendingReviews == nil {
+		m.pendingReviews = make(map[string][]pendingComment)
+	}
+	m.pendingReviews[key] = comments
+	m.statusOverride = true
+	if m.diffMode {
+		m.refreshDiffContent()
+	}
+}
+
+// startReview opens the review composer for the pull request in the detail
+// view. Pending line comments are sent along with it.
+func (m *model) startReview() {
+	if m.detailItem.Kind != "PR" || m.detailItem.Title == "" {
+		return
+	}
+	m.commentMode = true
+	m.composeKind = composeReview
+	m.reviewEvent = reviewEventComment
+	m.actionItem = issueItem{
+		TitleText: m.detailItem.Title,
+		Repo:      m.detailItem.Repo,
+		Number:    m.detailItem.Number,
+		URL:       m.detailTarget.URL,
+		Kind:      m.detailItem.Kind,
+	}
+	m.textarea.Focus()
+	m.textarea.SetValue("")
+}
+
+// reopenComposer brings back a composer whose text was confirmed and then
+// rejected by GitHub, so it can be fixed and sent again. A composer or
+// prompt opened in the meantime is left alone.
+func (m *model) reopenComposer(kind int) {
+	if m.commentMode || m.confirmMode || m.pickerMode {
+		return
+	}
+	m.commentMode = true
+	m.composeKind = kind
+	m.textarea.Focus()
+	m.textarea.SetValue(m.pendingBody)
+}
+
+func (m *model) openPendingReview() {
+	if len(m.pendingReviews[m.detailTarget.URL]) == 0 {
+		m.status = "No pending review comments"
+		m.statusOverride = true
+		return
+	}
+	m.pendingMode = true
+	m.pendingIndex = 0
+}
+
+func (m model) updatePending(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := m.detailTarget.URL
+	comments := m.pendingReviews[key]
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "P":
+		m.pendingMode = false
+	case "j", "down":
+		m.pendingIndex = min(m.pendingIndex+1, max(0, len(comments)-1))
+	case "k", "up":
+		m.pendingIndex = max(0, m.pendingIndex-1)
+	case "e", "enter":
+		if m.pendingIndex < len(comments) {
+			m.commentMode = true
+			m.composeKind = composeLineComment
+			m.editingPending = m.pendingIndex
+			m.actionItem = m.detailTarget
+			m.textarea.Focus()
+			m.textarea.SetValue(comments[m.pendingIndex].Body)
+		}
+	case "x":
+		if m.pendingIndex < len(comments) {
+			m.pendingReviews[key] = append(comments[:m.pendingIndex:m.pendingIndex], comments[m.pendingIndex+1:]...)
+			m.pendingIndex = min(m.pendingIndex, max(0, len(m.pendingReviews[key])-1))
+			if len(m.pendingReviews[key]) == 0 {
+				delete(m.pendingReviews, key)
+				m.pendingMode = false
+			}
+			if m.diffMode {
+				m.refreshDiffContent()
+			}
+		}
+	case "D":
+		m.confirmMode = true
+		m.confirmAction = confirmDiscardReview
+	case "R":
+		m.startReview()
+	}
+	return m, nil
+}
+
+// pendingView lists the pending comments under a fixed header. Entries can
+// be taller than the screen, so the lines are windowed to keep the
+// selected entry in view, from its location line down.
+func (m model) pendingView() string {
+	comments := m.pendingReviews[m.detailTarget.URL]
+	width := m.width - 2
+	header := []string{
+		m.styles.AccentText.Render(fmt.Sprintf("Pending review • %d comments", len(comments))),
+		m.styles.MetaText.Render(fmt.Sprintf("%s • #%d", m.detailItem.Repo, m.detailItem.Number)),
+		"",
+	}
+	var lines []string
+	selStart, selEnd := 0, 0
+	for i, c := range comments {
+		branch := m.styles.TreeLine.Render("|- ")
+		location := m.styles.RowTitle.Render(c.location())
+		if i == m.pendingIndex {
+			branch = m.styles.RowCursor.Render("▶  ")
+			location = m.styles.RowTitleSelected.Render(c.location())
+			selStart = len(lines)
+		}
+		lines = append(lines, branch+location)
+		for _, raw := range c.Context[max(0, len(c.Context)-maxThreadHunk):] {
+			lines = append(lines, ansi.Truncate(m.styles.TreeLine.Render("|  ")+highlightDiffLine(raw, m.styles), max(10, width), "…"))
+		}
+		lines = append(lines, strings.Split(prefixLines(renderMarkdown(c.Body, width-4, m.styles), m.styles.TreeLine.Render("|  ")), "\n")...)
+		if i == m.pendingIndex {
+			selEnd = len(lines)
+		}
+	}
+
+	rows := max(1, m.bodyHeight()-len(header))
+	if len(lines) > rows {
+		rows = max(1, rows-1)
+		start := max(0, min(selStart, selEnd-rows))
+		start = min(start, len(lines)-rows)
+		end := start + rows
+		more := m.styles.MutedText.Render(fmt.Sprintf("  comment %d/%d • lines %d-%d of %d", m.pendingIndex+1, len(comments), start+1, end, len(lines)))
+		lines = append(lines[start:end:end], more)
+	}
+	return strings.Join(append(header, lines...), "\n")
+}
+
+func pendingPayloads(comments []pendingComment) []map[string]any {
+	payloads := make([]map[string]any, 0, len(comments))
+	for _, c := range comments {
+		payloads = append(payloads, c.apiPayload())
+	}
+	return payloads
+}
@@ -0,0 +1,397 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxPeoplePages = 5
+
+// reviewerState is a requested reviewer (user or team) or a user who has
+// already reviewed. State is "pending" until a review is submitted.
+type reviewerState struct {
+	Login string
+	Team  bool
+	State string
+}
+
+// buildReviewers merges the outstanding review requests with the latest
+// review of every other user. Pending requests come first.
+func buildReviewers(requested, teams []string, latest map[string]string) []reviewerState {
+	reviewers := make([]reviewerState, 0, len(requested)+len(teams)+len(latest))
+	seen := make(map[string]bool)
+	for _, login := range requested {
+		reviewers = append(reviewers, reviewerState{Login: login, State: "pending"})
+		seen[login] = true
+	}
+	for _, slug := range teams {
+		reviewers = append(reviewers, reviewerState{Login: slug, Team: true, State: "pending"})
+	}
+	reviewed := make([]string, 0, len(latest))
+	for login := range latest {
+		if !seen[login] {
+			reviewed = append(reviewed, login)
+		}
+	}
+	sort.Strings(reviewed)
+	for _, login := range reviewed {
+		state := ""
+		switch latest[login] {
+		case "APPROVED":
+			state = "approved"
+		case "CHANGES_REQUESTED":
+			state = "changes requested"
+		case "COMMENTED":
+			state = "commented"
+		case "DISMISSED":
+			state = "dismissed"
+		default:
+			continue
+		}
+		reviewers = append(reviewers, reviewerState{Login: login, State: state})
+	}
+	return reviewers
+}
+
+func formatReviewers(reviewers []reviewerState, repo string) string {
+	if len(reviewers) == 0 {
+		return "-"
+	}
+	owner, _, _ := strings.Cut(repo, "/")
+	parts := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		name := r.Login
+		if r.Team {
+			name = "@" + owner + "/" + r.Login
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, r.State))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fetchViewer(ctx context.Context, token string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	if err != nil {
+		return "", err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", readAPIError(resp)
+	}
+
+	var payload struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", err
+	}
+	return sanitizeLine(payload.Login), nil
+}
+
+// fetchRepoNames pages through a repository listing endpoint (assignees,
+// teams) and collects one string field of every entry.
+func fetchRepoNames(ctx context.Context, token, apiURL, field string) ([]string, error) {
+	var names []string
+	for page := 1; page <= maxPeoplePages; page++ {
+		endpoint, err := url.Parse(apiURL)
+		if err != nil {
+			return nil, err
+		}
+		params := endpoint.Query()
+		params.Set("per_page", "100")
+		params.Set("page", strconv.Itoa(page))
+		endpoint.RawQuery = params.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		addJSONHeaders(req, token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := readAPIError(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var payload []map[string]any
+		err = json.NewDecoder(resp.Body).Decode(&payload)
+		hasNext := hasLinkRel(resp.Header.Get("Link"), "next")
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range payload {
+			if name, ok := entry[field].(string); ok && name != "" {
+				names = append(names, sanitizeLine(name))
+			}
+		}
+		if !hasNext {
+			break
+		}
+	}
+	return names, nil
+}
+
+func fetchAssignableUsers(ctx context.Context, token, repo string) ([]string, error) {
+	return fetchRepoNames(ctx, token, fmt.Sprintf("https://api.github.com/repos/%s/assignees", repo), "login")
+}
+
+// fetchRepoTeams lists the teams with access to a repository. Only
+// organization repositories have teams, and listing them needs admin
+// rights, so callers treat failures as "no teams".
+func fetchRepoTeams(ctx context.Context, token, repo string) ([]string, error) {
+	return fetchRepoNames(ctx, token, fmt.Sprintf("https://api.github.com/repos/%s/teams", repo), "slug")
+}
+
+func sendPeopleRequest(ctx context.Context, token, method, apiURL string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
+
+func editAssignees(ctx context.Context, token string, item issueItem, add, remove []string) error {
+	if item.Repo == "" || item.Number == 0 {
+		return errors.New("missing repo or number")
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/assignees", item.Repo, item.Number)
+	if len(add) > 0 {
+		if err := sendPeopleRequest(ctx, token, http.MethodPost, apiURL, map[string][]string{"assignees": add}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := sendPeopleRequest(ctx, token, http.MethodDelete, apiURL, map[string][]string{"assignees": remove}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewerChange holds the users and team slugs to request or un-request.
+type reviewerChange struct {
+	AddUsers    []string
+	AddTeams    []string
+	RemoveUsers []string
+	RemoveTeams []string
+}
+
+func editReviewers(ctx context.Context, token string, item issueItem, change reviewerChange) error {
+	if item.Repo == "" || item.Number == 0 {
+		return errors.New("missing repo or number")
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d/requested_reviewers", item.Repo, item.Number)
+	if len(change.AddUsers)+len(change.AddTeams) > 0 {
+		payload := map[string][]string{"reviewers": change.AddUsers, "team_reviewers": change.AddTeams}
+		if err := sendPeopleRequest(ctx, token, http.MethodPost, apiURL, payload); err != nil {
+			return err
+		}
+	}
+	if len(change.RemoveUsers)+len(change.RemoveTeams) > 0 {
+		payload := map[string][]string{"reviewers": change.RemoveUsers, "team_reviewers": change.RemoveTeams}
+		if err := sendPeopleRequest(ctx, token, http.MethodDelete, apiURL, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffChecked splits a picker's changes into additions and removals.
+func diffChecked(before, after []string) ([]string, []string) {
+	had := make(map[string]bool, len(before))
+	for _, v := range before {
+		had[v] = true
+	}
+	has := make(map[string]bool, len(after))
+	var add, remove []string
+	for _, v := range after {
+		has[v] = true
+		if !had[v] {
+			add = append(add, v)
+		}
+	}
+	for _, v := range before {
+		if !has[v] {
+			remove = append(remove, v)
+		}
+	}
+	return add, remove
+}
+
+// detailReady reports whether the detail of the current target is loaded,
+// which the people pickers need to know who is assigned or requested.
+func (m model) detailReady() bool {
+	return m.detailVisible() && m.detailItem.Title != "" && m.detailItem.URL == m.detailTarget.URL
+}
+
+func (m *model) startPeoplePicker(kind int) tea.Cmd {
+	if !m.detailReady() || (kind == pickReviewers && m.detailItem.Kind != "PR") {
+		return nil
+	}
+	m.pickerTarget = m.detailTarget
+	repo := m.detailTarget.Repo
+	if _, ok := m.repoAssignees[repo]; ok && (kind == pickAssignees || m.repoTeams[repo] != nil) {
+		m.openPeoplePicker(kind)
+		return nil
+	}
+	m.pickerLoading = kind
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Loading people for %s...", repo)
+	m.statusOverride = true
+	return fetchPeopleCmd(repo, kind == pickReviewers)
+}
+
+func (m *model) openPeoplePicker(kind int) {
+	repo := m.pickerTarget.Repo
+	var options []pickerOption
+	var checked []string
+	title := fmt.Sprintf("Assignees • %s#%d", repo, m.pickerTarget.Number)
+	if kind == pickReviewers {
+		title = fmt.Sprintf("Reviewers • %s#%d", repo, m.pickerTarget.Number)
+		owner, _, _ := strings.Cut(repo, "/")
+		for _, slug := range m.repoTeams[repo] {
+			options = append(options, pickerOption{Value: "team:" + slug, Label: "@" + owner + "/" + slug, Hint: "team"})
+		}
+		states := make(map[string]string)
+		for _, r := range m.detailItem.Reviewers {
+			if r.Team {
+				if r.State == "pending" {
+					checked = append(checked, "team:"+r.Login)
+				}
+				continue
+			}
+			states[r.Login] = r.State
+			if r.State == "pending" {
+				checked = append(checked, "user:"+r.Login)
+			}
+		}
+		for _, login := range m.repoAssignees[repo] {
+			if login == m.detailItem.Author {
+				continue
+			}
+			options = append(options, pickerOption{Value: "user:" + login, Label: login, Hint: states[login]})
+		}
+	} else {
+		for _, login := range m.repoAssignees[repo] {
+			options = append(options, pickerOption{Value: login, Label: login})
+		}
+		checked = append(checked, m.detailItem.Assignees...)
+	}
+	m.picker = newPicker(title, options, checked, true)
+	m.pickerKind = kind
+	m.pickerMode = true
+}
+
+// applyPeople sends what changed in the assignee or reviewer picker.
+func (m *model) applyPeople() tea.Cmd {
+	before := make([]string, 0, len(m.picker.initial))
+	for _, o := range m.picker.options {
+		if m.picker.initial[o.Value] {
+			before = append(before, o.Value)
+		}
+	}
+	add, remove := diffChecked(before, m.picker.Checked())
+	m.actionLoading = true
+	m.statusOverride = true
+	if m.pickerKind == pickAssignees {
+		m.status = "Updating assignees..."
+		return editAssigneesCmd(m.pickerTarget, add, remove, "Assignees updated")
+	}
+
+	var change reviewerChange
+	for _, v := range add {
+		if slug, ok := strings.CutPrefix(v, "team:"); ok {
+			change.AddTeams = append(change.AddTeams, slug)
+		} else {
+			change.AddUsers = append(change.AddUsers, strings.TrimPrefix(v, "user:"))
+		}
+	}
+	for _, v := range remove {
+		if slug, ok := strings.CutPrefix(v, "team:"); ok {
+			change.RemoveTeams = append(change.RemoveTeams, slug)
+		} else {
+			change.RemoveUsers = append(change.RemoveUsers, strings.TrimPrefix(v, "user:"))
+		}
+	}
+	m.status = "Updating reviewers..."
+	return editReviewersCmd(m.pickerTarget, change, "Reviewers updated")
+}
+
+func (m *model) assignMe() tea.Cmd {
+	target, ok := m.actionTarget()
+	if !ok {
+		return nil
+	}
+	if m.viewer == "" {
+		m.status = "Your GitHub login is not known yet"
+		m.statusOverride = true
+		return viewerCmd()
+	}
+	if m.detailReady() && m.detailItem.URL == target.URL {
+		for _, login := range m.detailItem.Assignees {
+			if login == m.viewer {
+				m.status = "Already assigned to you"
+				m.statusOverride = true
+				return nil
+			}
+		}
+	}
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Assigning %s#%d to %s...", target.Repo, target.Number, m.viewer)
+	m.statusOverride = true
+	return editAssigneesCmd(target, []string{m.viewer}, nil, fmt.Sprintf("Assigned %s#%d to %s", target.Repo, target.Number, m.viewer))
+}
+
+func (m *model) removeMeAsReviewer() tea.Cmd {
+	target, ok := m.actionTarget()
+	if !ok || target.Kind != "PR" {
+		return nil
+	}
+	if m.viewer == "" {
+		m.status = "Your GitHub login is not known yet"
+		m.statusOverride = true
+		return viewerCmd()
+	}
+	m.actionLoading = true
+	m.status = fmt.Sprintf("Removing %s as reviewer of %s#%d...", m.viewer, target.Repo, target.Number)
+	m.statusOverride = true
+	change := reviewerChange{RemoveUsers: []string{m.viewer}}
+	return editReviewersCmd(target, change, fmt.Sprintf("Removed %s as reviewer of %s#%d", m.viewer, target.Repo, target.Number))
+}
@@ -0,0 +1,277 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	pickerNone = iota
+	pickerSubmit
+	pickerCancel
+)
+
+type pickerOption struct {
+	Value string
+	Label string
+	Color string
+	Hint  string
+}
+
+// picker is a fuzzy-searchable list of options. In multi mode tab toggles
+// options and enter submits the checked set; otherwise enter submits the
+// option under the cursor. A freeText picker also submits typed text that
+// matches no option.
+type picker struct {
+	title    string
+	options  []pickerOption
+	matches  []int
+	checked  map[string]bool
+	initial  map[string]bool
+	multi    bool
+	freeText bool
+	cursor   int
+	input    textinput.Model
+	errorMsg string
+}
+
+func newPicker(title string, options []pickerOption, checked []string, multi bool) picker {
+	input := textinput.New()
+	input.Placeholder = "Type to filter..."
+	input.Prompt = "/ "
+	input.Focus()
+	p := picker{
+		title:   title,
+		options: options,
+		checked: make(map[string]bool),
+		initial: make(map[string]bool),
+		multi:   multi,
+		input:   input,
+	}
+	for _, value := range checked {
+		p.checked[value] = true
+		p.initial[value] = true
+	}
+	p.filter()
+	return p
+}
+
+// filter recomputes the visible options for the current query, best fuzzy
+// matches first.
+func (p *picker) filter() {
+	query := strings.TrimSpace(p.input.Value())
+	p.matches = p.matches[:0]
+	if query == "" {
+		for i := range p.options {
+			p.matches = append(p.matches, i)
+		}
+	} else {
+		labels := make([]string, len(p.options))
+		for i, o := range p.options {
+			labels[i] = o.Label
+		}
+		for _, match := range fuzzy.Find(query, labels) {
+			p.matches = append(p.matches, match.Index)
+		}
+	}
+	p.cursor = min(p.cursor, max(0, len(p.matches)-1))
+}
+
+func (p picker) current() (pickerOption, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.matches) {
+		return pickerOption{}, false
+	}
+	return p.options[p.matches[p.cursor]], true
+}
+
+func (p picker) Update(msg tea.KeyMsg) (picker, int) {
+	switch msg.String() {
+	case "esc":
+		return p, pickerCancel
+	case "enter":
+		if !p.multi && !p.freeText {
+			if _, ok := p.current(); !ok {
+				return p, pickerNone
+			}
+		}
+		return p, pickerSubmit
+	case "up", "ctrl+p", "ctrl+k":
+		p.cursor = max(0, p.cursor-1)
+		return p, pickerNone
+	case "down", "ctrl+n", "ctrl+j":
+		p.cursor = min(p.cursor+1, max(0, len(p.matches)-1))
+		return p, pickerNone
+	case "tab":
+		if option, ok := p.current(); ok && p.multi {
+			p.checked[option.Value] = !p.checked[option.Value]
+		}
+		return p, pickerNone
+	}
+	p.input, _ = p.input.Update(msg)
+	p.filter()
+	return p, pickerNone
+}
+
+// remove drops an option, e.g. after the thing it stands for was deleted.
+func (p *picker) remove(value string) {
+	for i, o := range p.options {
+		if o.Value == value {
+			p.options = append(p.options[:i], p.options[i+1:]...)
+			break
+		}
+	}
+	delete(p.checked, value)
+	delete(p.initial, value)
+	p.filter()
+}
+
+// Query returns the text typed into the filter.
+func (p picker) Query() string {
+	return strings.TrimSpace(p.input.Value())
+}
+
+// Checked returns the checked values in option order.
+func (p picker) Checked() []string {
+	values := make([]string, 0, len(p.checked))
+	for _, o := range p.options {
+		if p.checked[o.Value] {
+			values = append(values, o.Value)
+		}
+	}
+	return values
+}
+
+// Changed reports whether the checked set differs from the one the picker
+// was opened with.
+func (p picker) Changed() bool {
+	for _, o := range p.options {
+		if p.checked[o.Value] != p.initial[o.Value] {
+			return true
+		}
+	}
+	return false
+}
+
+func (p picker) View(width, height int, styles uiStyles) string {
+	p.input.Width = max(10, width-lipgloss.Width(p.input.Prompt)-1)
+	lines := []string{styles.AccentText.Render(p.title), p.input.View()}
+	if p.errorMsg != "" {
+		lines = append(lines, styles.StatusErr.Render(p.errorMsg))
+	}
+	lines = append(lines, "")
+
+	rows := max(1, height-len(lines)-1)
+	start := 0
+	if p.cursor >= rows {
+		start = p.cursor - rows + 1
+	}
+	end := min(len(p.matches), start+rows)
+	if len(p.matches) == 0 {
+		lines = append(lines, styles.MutedText.Render("  (no matches)"))
+	}
+	for i := start; i < end; i++ {
+		o := p.options[p.matches[i]]
+		cursor := "  "
+		if i == p.cursor {
+			cursor = styles.RowCursor.Render("│ ")
+		}
+		box := ""
+		if p.multi {
+			box = styles.MutedText.Render("[ ] ")
+			if p.checked[o.Value] {
+				box = styles.StateOpen.Render("[x] ")
+			}
+		}
+		label := styles.RowTitle.Render(o.Label)
+		if i == p.cursor {
+			label = styles.RowTitleSelected.Render(o.Label)
+		}
+		if o.Color != "" {
+			label = labelChip(issueLabel{Name: o.Label, Color: o.Color})
+		}
+		line := cursor + box + label
+		if o.Hint != "" {
+			line += "  " + styles.MutedText.Render(o.Hint)
+		}
+		lines = append(lines, ansi.Truncate(line, max(10, width), "…"))
+	}
+	if len(p.matches) > rows {
+		lines = append(lines, styles.MutedText.Render(fmt.Sprintf("  %d/%d", p.cursor+1, len(p.matches))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	if m.pickerKind == pickDrafts && msg.String() == "ctrl+x" {
+		if option, ok := m.picker.current(); ok {
+			return m, m.deleteDraft(option.Value)
+		}
+		return m, nil
+	}
+	var action int
+	m.picker, action = m.picker.Update(msg)
+	switch action {
+	case pickerCancel:
+		m.pickerMode = false
+		switch m.pickerKind {
+		case pickDuplicate, pickReplies, pickSaveReply:
+			m.commentMode = true
+			m.textarea.Focus()
+		case pickIssueTemplate, pickIssueField:
+			m.newIssue = nil
+		}
+	case pickerSubmit:
+		m.pickerMode = false
+		switch m.pickerKind {
+		case pickLabels:
+			if !m.picker.Changed() {
+				return m, nil
+			}
+			return m, m.applyLabels(m.picker.Checked())
+		case pickDrafts:
+			if option, ok := m.picker.current(); ok {
+				m.openDraft(option.Value)
+			}
+		case pickDuplicate:
+			return m, m.submitDuplicate()
+		case pickIssueRepo:
+			return m, m.submitRepo()
+		case pickIssueTemplate:
+			if option, ok := m.picker.current(); ok {
+				return m, m.submitTemplate(option.Value)
+			}
+		case pickIssueField:
+			return m, m.submitIssueChoice()
+		case pickReplies:
+			m.commentMode = true
+			m.textarea.Focus()
+			if option, ok := m.picker.current(); ok {
+				m.insertReply(option.Value)
+				return m, m.noteDraft()
+			}
+		case pickSaveReply:
+			m.commentMode = true
+			m.textarea.Focus()
+			name := m.picker.Query()
+			if option, ok := m.picker.current(); ok && name == "" {
+				name = option.Value
+			}
+			return m, m.saveReply(name)
+		case pickAssignees, pickReviewers:
+			if !m.picker.Changed() {
+				return m, nil
+			}
+			return m, m.applyPeople()
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	previewOff = iota
+	previewLocal
+	previewGitHub
+)
+
+const (
+	previewSideBySideWidth = 100
+	markdownDebounce       = 800 * time.Millisecond
+)
+
+type markdownTickMsg struct {
+	seq int
+}
+
+type markdownResult struct {
+	seq    int
+	source string
+	text   string
+	err    error
+}
+
+// renderMarkdownAPI asks GitHub to render text as it would in the given
+// repository (resolving #references and @mentions) and returns the HTML.
+func renderMarkdownAPI(ctx context.Context, token, repo, text string) (string, error) {
+	payload := map[string]string{"text": text, "mode": "gfm"}
+	if repo != "" {
+		payload["context"] = repo
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/markdown", strings.NewReader(string(raw)))
+	if err != nil {
+		return "", err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", readAPIError(resp)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+var (
+	htmlBreakTags = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|tr|pre|blockquote|table|ul|ol)>`)
+	htmlItemTags  = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlBoxTags   = regexp.MustCompile(`(?i)<input[^>]*checked[^>]*>`)
+	htmlEmptyBox  = regexp.MustCompile(`(?i)<input[^>]*>`)
+	htmlRuleTags  = regexp.MustCompile(`(?i)<hr\s*/?>`)
+	htmlAnyTag    = regexp.MustCompile(`<[^>]*>`)
+	blankRuns     = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText flattens GitHub's rendered HTML into readable text. It keeps
+// the line structure and list markers; styling is left to the local preview.
+func htmlToText(src string) string {
+	text := htmlBreakTags.ReplaceAllString(src, "$0\n")
+	text = htmlItemTags.ReplaceAllString(text, "• ")
+	text = htmlBoxTags.ReplaceAllString(text, "[x]")
+	text = htmlEmptyBox.ReplaceAllString(text, "[ ]")
+	text = htmlRuleTags.ReplaceAllString(text, "\n────\n")
+	text = htmlAnyTag.ReplaceAllString(text, "")
+	text = sanitizeText(html.UnescapeString(text))
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+func renderMarkdownCmd(seq int, repo, text string) tea.Cmd {
+	return func() tea.Msg {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return markdownResult{seq: seq, err: errors.New("GITHUB_TOKEN is required")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		rendered, err := renderMarkdownAPI(ctx, token, repo, text)
+		if err != nil {
+			return markdownResult{seq: seq, source: text, err: err}
+		}
+		return markdownResult{seq: seq, source: text, text: htmlToText(rendered)}
+	}
+}
+
+func (m model) previewSideBySide() bool {
+	return m.width >= previewSideBySideWidth
+}
+
+// previewHidesEditor reports whether the preview is shown instead of the
+// textarea, which happens on narrow terminals.
+func (m model) previewHidesEditor() bool {
+	return m.previewMode != previewOff && !m.previewSideBySide()
+}
+
+// sizeComposer fits the textarea to the terminal, leaving room for the
+// preview when it is shown beside it.
+func (m *model) sizeComposer() {
+	width := max(20, m.width-4)
+	if m.previewMode != previewOff && m.previewSideBySide() {
+		width = max(20, m.width/2-3)
+	}
+	m.textarea.SetWidth(width)
+	m.textarea.SetHeight(max(3, max(6, m.height-10)-m.completionHeight()))
+}
+
+// cyclePreview steps through off, local rendering and GitHub rendering.
+func (m *model) cyclePreview() tea.Cmd {
+	m.previewMode = (m.previewMode + 1) % 3
+	m.sizeComposer()
+	if m.previewMode == previewGitHub {
+		return m.refreshServerPreview()
+	}
+	return nil
+}
+
+func (m *model) refreshServerPreview() tea.Cmd {
+	m.markdownSeq++
+	m.markdownLoading = true
+	m.markdownErr = nil
+	return renderMarkdownCmd(m.markdownSeq, m.actionItem.Repo, m.textarea.Value())
+}
+
+// notePreviewEdit re-renders the GitHub preview shortly after typing stops.
+func (m *model) notePreviewEdit() tea.Cmd {
+	if m.previewMode != previewGitHub {
+		return nil
+	}
+	m.markdownSeq++
+	seq := m.markdownSeq
+	return tea.Tick(markdownDebounce, func(time.Time) tea.Msg {
+		return markdownTickMsg{seq: seq}
+	})
+}
+
+func (m *model) handleMarkdownTick(msg markdownTickMsg) tea.Cmd {
+	if msg.seq != m.markdownSeq || m.previewMode != previewGitHub || !m.commentMode {
+		return nil
+	}
+	return m.refreshServerPreview()
+}
+
+func (m *model) handleMarkdownResult(msg markdownResult) {
+	if msg.seq != m.markdownSeq {
+		return
+	}
+	m.markdownLoading = false
+	m.markdownErr = msg.err
+	if msg.err == nil {
+		m.markdownText = msg.text
+		m.markdownFor = msg.source
+	}
+}
+
+// composerPreview renders the draft as the detail view would render the
+// posted comment, or GitHub's own rendering of it.
+func (m model) composerPreview(width, height int) string {
+	title := "Preview"
+	var body string
+	switch m.previewMode {
+	case previewGitHub:
+		title = "Preview (GitHub)"
+		switch {
+		case m.markdownErr != nil:
+			body = m.styles.StatusErr.Render("unavailable: " + m.markdownErr.Error())
+		case m.markdownLoading && m.markdownText == "":
+			body = m.spinner.View() + " " + m.styles.MutedText.Render("Rendering...")
+		default:
+			body = m.markdownText
+			switch {
+			case m.markdownLoading:
+				title += " " + m.spinner.View()
+			case m.markdownFor != m.textarea.Value():
+				title += " • outdated, type to refresh"
+			}
+		}
+	default:
+		author := m.viewer
+		if author == "" {
+			author = "you"
+		}
+		body = renderComment(issueComment{Author: author, Body: m.textarea.Value(), Updated: time.Now()}, false, width, m.styles)
+	}
+
+	lines := strings.Split(body, "\n")
+	if len(lines) > height {
+		lines = append(lines[:max(0, height-1)], m.styles.MutedText.Render(fmt.Sprintf("… %d more lines", len(lines)-height+1)))
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	return m.styles.AccentText.Render(title) + "\n" + strings.Join(lines, "\n")
+}
+
+// composerBody lays out the textarea and, when enabled, the preview.
+func (m model) composerBody() string {
+	editor := m.textarea.View()
+	if m.previewMode == previewOff {
+		return editor
+	}
+	height := lipgloss.Height(editor) - 1
+	if !m.previewSideBySide() {
+		return m.composerPreview(max(20, m.width-4), max(1, height))
+	}
+	width := max(20, m.width-lipgloss.Width(editor)-4)
+	preview := lipgloss.NewStyle().Width(width).Height(height + 1).Render(m.composerPreview(width, max(1, height)))
+	return lipgloss.JoinHorizontal(lipgloss.Top, editor, "  ", preview)
+}
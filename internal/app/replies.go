@@ -0,0 +1,178 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const repliesFileName = "replies.json"
+
+// savedReply is a canned response. Bodies may use the placeholders
+// {{author}}, {{repo}}, {{number}}, {{title}} and {{me}}.
+type savedReply struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+type repliesSavedResult struct {
+	name string
+	err  error
+}
+
+func repliesFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "github_inbox_tui", repliesFileName), nil
+}
+
+func loadSavedReplies() ([]savedReply, error) {
+	path, err := repliesFilePath()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var replies []savedReply
+	if err := json.Unmarshal(raw, &replies); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return replies, nil
+}
+
+func saveRepliesCmd(replies []savedReply, name string) tea.Cmd {
+	raw, err := json.MarshalIndent(replies, "", "  ")
+	return func() tea.Msg {
+		if err != nil {
+			return repliesSavedResult{name: name, err: err}
+		}
+		path, err := repliesFilePath()
+		if err != nil {
+			return repliesSavedResult{name: name, err: err}
+		}
+		return repliesSavedResult{name: name, err: writeFileAtomic(path, raw)}
+	}
+}
+
+// expandReply fills in the template placeholders of a saved reply.
+func expandReply(body string, item issueItem, author, me string) string {
+	return strings.NewReplacer(
+		"{{author}}", author,
+		"{{repo}}", item.Repo,
+		"{{number}}", strconv.Itoa(item.Number),
+		"{{title}}", item.TitleText,
+		"{{me}}", me,
+	).Replace(body)
+}
+
+// composeAuthor returns the author of the item being commented on, from
+// the list row or, failing that, the loaded detail.
+func (m model) composeAuthor() string {
+	if m.actionItem.Author != "" {
+		return m.actionItem.Author
+	}
+	if m.detailItem.URL == m.actionItem.URL {
+		return m.detailItem.Author
+	}
+	return ""
+}
+
+func (m *model) openRepliesPicker() {
+	if m.repliesErr != nil {
+		m.status = fmt.Sprintf("Error: %s", m.repliesErr.Error())
+		m.statusOverride = true
+		return
+	}
+	if len(m.replies) == 0 {
+		m.status = "No saved replies yet; ctrl+s saves the current text as one"
+		m.statusOverride = true
+		return
+	}
+	options := make([]pickerOption, 0, len(m.replies))
+	for _, r := range m.replies {
+		first, _, _ := strings.Cut(strings.TrimSpace(r.Body), "\n")
+		options = append(options, pickerOption{Value: r.Name, Label: r.Name, Hint: first})
+	}
+	m.picker = newPicker("Saved replies", options, nil, false)
+	m.pickerKind = pickReplies
+	m.openComposerPicker()
+}
+
+// openComposerPicker shows a picker in place of the composer; updatePicker
+// brings the composer back once it is dismissed.
+func (m *model) openComposerPicker() {
+	m.commentMode = false
+	m.textarea.Blur()
+	m.pickerMode = true
+}
+
+func (m *model) insertReply(name string) {
+	for _, r := range m.replies {
+		if r.Name != name {
+			continue
+		}
+		m.textarea.InsertString(expandReply(r.Body, m.actionItem, m.composeAuthor(), m.viewer))
+		m.status = fmt.Sprintf("Inserted %q", name)
+		m.statusOverride = true
+		return
+	}
+}
+
+// openSaveReply asks for a name for the composer text. Existing names are
+// offered so a reply can be replaced; typing a new name creates one.
+func (m *model) openSaveReply() {
+	if strings.TrimSpace(m.textarea.Value()) == "" {
+		m.status = "Nothing to save"
+		m.statusOverride = true
+		return
+	}
+	if m.repliesErr != nil {
+		m.status = fmt.Sprintf("Error: %s", m.repliesErr.Error())
+		m.statusOverride = true
+		return
+	}
+	options := make([]pickerOption, 0, len(m.replies))
+	for _, r := range m.replies {
+		options = append(options, pickerOption{Value: r.Name, Label: r.Name, Hint: "replace"})
+	}
+	m.picker = newPicker("Save reply as", options, nil, false)
+	m.picker.freeText = true
+	m.picker.input.Placeholder = "Name for the new reply..."
+	m.pickerKind = pickSaveReply
+	m.openComposerPicker()
+}
+
+func (m *model) saveReply(name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	reply := savedReply{Name: name, Body: strings.TrimSpace(m.textarea.Value())}
+	replaced := false
+	for i := range m.replies {
+		if m.replies[i].Name == name {
+			m.replies[i] = reply
+			replaced = true
+		}
+	}
+	if !replaced {
+		m.replies = append(m.replies, reply)
+		sort.SliceStable(m.replies, func(i, j int) bool {
+			return strings.ToLower(m.replies[i].Name) < strings.ToLower(m.replies[j].Name)
+		})
+	}
+	return saveRepliesCmd(m.replies, name)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func composingModel(t *testing.T) model {
+	t.Helper()
+	next, _ := NewProgramModel().Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m := next.(model)
+	m.loading = false
+	m.replies = []savedReply{{Name: "thanks", Body: "Thanks {{author}}!"}}
+	m.actionItem = issueItem{Repo: "octo/repo", Number: 7, Kind: "Issue", Author: "octocat"}
+	m.commentMode = true
+	m.composeKind = composeComment
+	m.textarea.Focus()
+	m.textarea.SetValue("Hello")
+	return m
+}
+
+func press(m model, key tea.KeyMsg) model {
+	next, _ := m.Update(key)
+	return next.(model)
+}
+
+func TestRepliesPickerReplacesComposer(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   tea.KeyMsg
+		title string
+	}{
+		{"insert", tea.KeyMsg{Type: tea.KeyCtrlR}, "Saved replies"},
+		{"save", tea.KeyMsg{Type: tea.KeyCtrlS}, "Save reply as"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := press(composingModel(t), tt.key)
+			view := m.View()
+			if !strings.Contains(view, tt.title) {
+				t.Fatalf("picker %q not shown:\n%s", tt.title, view)
+			}
+			if strings.Contains(view, "New Comment") {
+				t.Fatalf("composer drawn over the picker:\n%s", view)
+			}
+
+			m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+			view = m.View()
+			if !m.commentMode || !strings.Contains(view, "New Comment") {
+				t.Fatalf("composer not restored after esc:\n%s", view)
+			}
+			if got := m.textarea.Value(); got != "Hello" {
+				t.Fatalf("composer text = %q, want %q", got, "Hello")
+			}
+		})
+	}
+}
+
+func TestRepliesPickerInsertsIntoComposer(t *testing.T) {
+	m := press(composingModel(t), tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.commentMode || m.pickerMode {
+		t.Fatalf("commentMode = %v, pickerMode = %v after choosing a reply", m.commentMode, m.pickerMode)
+	}
+	if got, want := m.textarea.Value(), "HelloThanks octocat!"; got != want {
+		t.Fatalf("composer text = %q, want %q", got, want)
+	}
+	if !strings.Contains(m.View(), "New Comment") {
+		t.Fatalf("composer not shown after inserting a reply:\n%s", m.View())
+	}
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	reviewEventComment        = "COMMENT"
+	reviewEventApprove        = "APPROVE"
+	reviewEventRequestChanges = "REQUEST_CHANGES"
+)
+
+var reviewEvents = []string{reviewEventComment, reviewEventApprove, reviewEventRequestChanges}
+
+func reviewEventLabel(event string) string {
+	switch event {
+	case reviewEventApprove:
+		return "Approve"
+	case reviewEventRequestChanges:
+		return "Request changes"
+	default:
+		return "Comment"
+	}
+}
+
+func nextReviewEvent(event string) string {
+	for i, e := range reviewEvents {
+		if e == event {
+			return reviewEvents[(i+1)%len(reviewEvents)]
+		}
+	}
+	return reviewEvents[0]
+}
+
+// reviewNeedsBody reports whether GitHub will reject the review without a
+// body: approvals never need one, and plain comments may consist of line
+// comments alone.
+func reviewNeedsBody(event string, lineComments int) bool {
+	switch event {
+	case reviewEventApprove:
+		return false
+	case reviewEventComment:
+		return lineComments == 0
+	default:
+		return true
+	}
+}
+
+// submitReview posts a review on a pull request, together with any pending
+// line comments.
+func submitReview(ctx context.Context, token string, item issueItem, event, body string, comments []pendingComment) error {
+	if item.Repo == "" || item.Number == 0 {
+		return errors.New("missing repo or number")
+	}
+	if reviewNeedsBody(event, len(comments)) && strings.TrimSpace(body) == "" {
+		return errors.New("review body is required")
+	}
+	payload := map[string]any{"event": event}
+	if body != "" {
+		payload["body"] = body
+	}
+	if len(comments) > 0 {
+		payload["comments"] = pendingPayloads(comments)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d/reviews", item.Repo, item.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// sanitizeText makes text written by other GitHub users safe to print. It
+// drops terminal escape sequences (CSI, OSC, DCS and friends), C0/C1 control
+// characters other than newline and tab, and Unicode bidi controls that could
+// reorder what is shown on screen.
+func sanitizeText(s string) string {
+	if s == "" {
+		return s
+	}
+	s = strings.ToValidUTF8(s, "�")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	var out strings.Builder
+	out.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == 0x1b:
+			i += escapeSequenceLength(s[i:])
+			continue
+		case r == 0x9b || r == 0x9d || r == 0x90 || r == 0x98 || r == 0x9e || r == 0x9f:
+			i += size + c1SequenceLength(s[i+size:], r)
+			continue
+		case r == '\n' || r == '\t':
+			out.WriteRune(r)
+		case r < 0x20 || r == 0x7f || (r >= 0x80 && r <= 0x9f):
+		case isBidiControl(r):
+		default:
+			out.WriteRune(r)
+		}
+		i += size
+	}
+	return out.String()
+}
+
+// sanitizeLine is sanitizeText for single-line fields such as titles, logins
+// and label names, where an embedded newline could fake extra rows.
+func sanitizeLine(s string) string {
+	s = sanitizeText(s)
+	if strings.ContainsAny(s, "\n\t") {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
+
+func isBidiControl(r rune) bool {
+	switch {
+	case r >= 0x202a && r <= 0x202e:
+		return true
+	case r >= 0x2066 && r <= 0x2069:
+		return true
+	case r == 0x200e || r == 0x200f || r == 0x061c:
+		return true
+	}
+	return false
+}
+
+// escapeSequenceLength returns the byte length of the escape sequence that
+// starts at s[0] == ESC. Unterminated string sequences swallow the rest of
+// the input.
+func escapeSequenceLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		return 2 + csiLength(s[2:])
+	case ']', 'P', 'X', '^', '_':
+		return 2 + stringSequenceLength(s[2:])
+	case '(', ')', '*', '+', '-', '.', '/', '#', '%', ' ':
+		return min(3, len(s))
+	default:
+		_, size := utf8.DecodeRuneInString(s[1:])
+		return 1 + size
+	}
+}
+
+func c1SequenceLength(s string, introducer rune) int {
+	if introducer == 0x9b {
+		return csiLength(s)
+	}
+	return stringSequenceLength(s)
+}
+
+// csiLength consumes parameter and intermediate bytes up to and including
+// the final byte of a control sequence.
+func csiLength(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 0x20 && c <= 0x3f:
+		case c >= 0x40 && c <= 0x7e:
+			return i + 1
+		default:
+			return i
+		}
+	}
+	return len(s)
+}
+
+// stringSequenceLength consumes an OSC/DCS/SOS/PM/APC payload up to and
+// including its terminator (BEL, ESC \ or the 8-bit ST).
+func stringSequenceLength(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 0x07:
+			return i + 1
+		case 0x1b:
+			if i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+			return i
+		}
+		if r, size := utf8.DecodeRuneInString(s[i:]); r == 0x9c {
+			return i + size
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,78 @@
+package app
+
+import "testing"
+
+func TestSanitizeText(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello, world", "hello, world"},
+		{"keeps tabs and newlines", "a\tb\nc", "a\tb\nc"},
+		{"crlf", "one\r\ntwo\r\n", "one\ntwo\n"},
+		{"lone cr", "fake\rprompt", "fakeprompt"},
+		{"csi color", "\x1b[31mred\x1b[0m", "red"},
+		{"csi cursor", "a\x1b[2J\x1b[Hb", "ab"},
+		{"csi with intermediates", "a\x1b[1 qb", "ab"},
+		{"unterminated csi", "a\x1b[12;", "a"},
+		{"osc title bel", "\x1b]0;pwned\x07text", "text"},
+		{"osc title st", "\x1b]2;pwned\x1b\\text", "text"},
+		{"osc 52 clipboard", "copy\x1b]52;c;cm0gLXJmIH4=\x07 me", "copy me"},
+		{"osc 8 hyperlink", "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", "click"},
+		{"unterminated osc", "safe\x1b]52;c;Zm9v", "safe"},
+		{"osc cut by escape", "\x1b]0;title\x1b[31mred", "red"},
+		{"dcs", "a\x1bPq#0;2;0;0;0\x1b\\b", "ab"},
+		{"unterminated dcs", "a\x1bP1$r", "a"},
+		{"apc and pm", "a\x1b_payload\x1b\\b\x1b^pm\x07c", "abc"},
+		{"charset designation", "a\x1b(0b", "ab"},
+		{"two byte escape", "a\x1bcb", "ab"},
+		{"trailing esc", "a\x1b", "a"},
+		{"8-bit csi", "a\u009b31mb", "ab"},
+		{"8-bit osc st", "a\u009d52;c;Zm9v\u009cb", "ab"},
+		{"8-bit osc bel", "a\u009d0;title\x07b", "ab"},
+		{"8-bit dcs", "a\u0090payload\u009cb", "ab"},
+		{"unterminated 8-bit osc", "a\u009d52;c;Zm9v", "a"},
+		{"other c1", "a\u0085b\u0084c", "abc"},
+		{"c0 controls", "a\x00b\x08c\x07d\x7f", "abcd"},
+		{"raw c1 byte", "a\x9b31mb", "a�31mb"},
+		{"invalid utf8", "a\xff\xfeb", "a�b"},
+		{"bidi override", "admin\u202egnp.exe", "admingnp.exe"},
+		{"bidi embeddings", "\u202ax\u202b\u202cy\u202d", "xy"},
+		{"bidi isolates", "\u2066a\u2067b\u2068c\u2069", "abc"},
+		{"bidi marks", "a\u200eb\u200fc\u061cd", "abcd"},
+		{"unicode kept", "héllo 世界 🎉", "héllo 世界 🎉"},
+		{"empty", "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sanitizeText(tc.in); got != tc.want {
+				t.Errorf("sanitizeText(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeLine(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Fix the parser", "Fix the parser"},
+		{"newline", "title\nfake row", "title fake row"},
+		{"crlf", "title\r\nfake row", "title fake row"},
+		{"tabs", "a\t\tb", "a b"},
+		{"escape then newline", "\x1b[2Kok\n\x1b[1Anope", "ok nope"},
+		{"osc 52", "login\x1b]52;c;Zm9v\x07", "login"},
+		{"bidi", "user\u202e", "user"},
+		{"spaces kept", "a  b", "a  b"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sanitizeLine(tc.in); got != tc.want {
+				t.Errorf("sanitizeLine(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
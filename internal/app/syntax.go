@@ -0,0 +1,248 @@
+package app
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type syntaxRules struct {
+	keywords     map[string]bool
+	types        map[string]bool
+	lineComments []string
+	blockComment [2]string
+	quotes       string
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	cLikeTypes = wordSet("bool byte char int int8 int16 int32 int64 uint uint8 uint16 uint32 uint64 float float32 float64 double long short string void error any rune usize isize i8 i16 i32 i64 u8 u16 u32 u64 f32 f64 str String Self")
+
+	syntaxByLang = map[string]syntaxRules{
+		"go": {
+			keywords:     wordSet("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota"),
+			types:        cLikeTypes,
+			lineComments: []string{"//"},
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       "\"'`",
+		},
+		"js": {
+			keywords:     wordSet("async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static super switch this throw try typeof var void while yield null undefined true false interface type enum implements readonly as"),
+			types:        wordSet("string number boolean any unknown never object Array Promise Record Map Set"),
+			lineComments: []string{"//"},
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       "\"'`",
+		},
+		"python": {
+			keywords:     wordSet("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield None True False self"),
+			types:        wordSet("int float str bool list dict set tuple bytes object"),
+			lineComments: []string{"#"},
+			quotes:       "\"'",
+		},
+		"rust": {
+			keywords:     wordSet("as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self static struct super trait true type unsafe use where while"),
+			types:        cLikeTypes,
+			lineComments: []string{"//"},
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       "\"",
+		},
+		"c": {
+			keywords:     wordSet("abstract auto break case catch class const continue default delete do else enum extern final finally for goto if implements import include define inline namespace new override package private protected public return sizeof static struct switch template this throw throws try typedef union using virtual volatile while null nullptr true false val var fun when object"),
+			types:        cLikeTypes,
+			lineComments: []string{"//"},
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       "\"'",
+		},
+		"shell": {
+			keywords:     wordSet("if then else elif fi for while until do done case esac in function return local export readonly set unset shift exit echo source"),
+			lineComments: []string{"#"},
+			quotes:       "\"'",
+		},
+		"ruby": {
+			keywords:     wordSet("alias and begin break case class def defined do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield require attr_reader attr_accessor"),
+			lineComments: []string{"#"},
+			quotes:       "\"'",
+		},
+		"yaml": {
+			keywords:     wordSet("true false null yes no on off"),
+			lineComments: []string{"#"},
+			quotes:       "\"'",
+		},
+		"json": {
+			keywords: wordSet("true false null"),
+			quotes:   "\"",
+		},
+		"sql": {
+			keywords:     wordSet("select from where and or not insert into values update set delete create table alter drop index join left right inner outer on group by order having limit as distinct null is in exists union all primary key foreign references SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE ALTER DROP INDEX JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT AS DISTINCT NULL IS IN EXISTS UNION ALL PRIMARY KEY FOREIGN REFERENCES"),
+			lineComments: []string{"--"},
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       "'\"",
+		},
+	}
+
+	langAliases = map[string]string{
+		"go": "go", "golang": "go",
+		"js": "js", "javascript": "js", "jsx": "js", "ts": "js", "typescript": "js", "tsx": "js", "mjs": "js", "cjs": "js",
+		"py": "python", "python": "python", "python3": "python",
+		"rs": "rust", "rust": "rust",
+		"c": "c", "h": "c", "cpp": "c", "cc": "c", "hpp": "c", "c++": "c", "java": "c", "kt": "c", "kotlin": "c", "cs": "c", "csharp": "c", "swift": "c", "scala": "c",
+		"sh": "shell", "bash": "shell", "zsh": "shell", "shell": "shell", "console": "shell",
+		"rb": "ruby", "ruby": "ruby",
+		"yml": "yaml", "yaml": "yaml", "toml": "yaml",
+		"json": "json", "jsonc": "json",
+		"sql": "sql", "diff": "diff", "patch": "diff",
+	}
+)
+
+// langFromPath guesses a highlighting language from a file name.
+func langFromPath(name string) string {
+	base := path.Base(name)
+	switch base {
+	case "Makefile", "Dockerfile", ".bashrc", ".zshrc":
+		return "shell"
+	}
+	return strings.TrimPrefix(path.Ext(base), ".")
+}
+
+type syntaxState struct {
+	inBlockComment bool
+}
+
+// highlightLine colors a single line of code. The state carries open block
+// comments from one line to the next.
+func highlightLine(line, lang string, state *syntaxState, styles uiStyles) string {
+	name := langAliases[strings.ToLower(lang)]
+	if name == "diff" {
+		return highlightDiffLine(line, styles)
+	}
+	rules, ok := syntaxByLang[name]
+	if !ok {
+		return styles.CodeText.Render(line)
+	}
+
+	var out strings.Builder
+	plain := strings.Builder{}
+	flush := func() {
+		if plain.Len() > 0 {
+			out.WriteString(styles.CodeText.Render(plain.String()))
+			plain.Reset()
+		}
+	}
+	emit := func(style lipgloss.Style, text string) {
+		flush()
+		out.WriteString(style.Render(text))
+	}
+
+	i := 0
+	if state.inBlockComment {
+		end := strings.Index(line, rules.blockComment[1])
+		if end < 0 {
+			return styles.SynComment.Render(line)
+		}
+		end += len(rules.blockComment[1])
+		emit(styles.SynComment, line[:end])
+		state.inBlockComment = false
+		i = end
+	}
+
+	for i < len(line) {
+		rest := line[i:]
+		if rules.blockComment[0] != "" && strings.HasPrefix(rest, rules.blockComment[0]) {
+			end := strings.Index(rest[len(rules.blockComment[0]):], rules.blockComment[1])
+			if end < 0 {
+				emit(styles.SynComment, rest)
+				state.inBlockComment = true
+				break
+			}
+			end += len(rules.blockComment[0]) + len(rules.blockComment[1])
+			emit(styles.SynComment, rest[:end])
+			i += end
+			continue
+		}
+		if hasAnyPrefix(rest, rules.lineComments) {
+			emit(styles.SynComment, rest)
+			break
+		}
+		c := rest[0]
+		if strings.IndexByte(rules.quotes, c) >= 0 {
+			end := 1
+			for end < len(rest) && rest[end] != c {
+				if rest[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(rest))
+			emit(styles.SynString, rest[:end])
+			i += end
+			continue
+		}
+		if c >= '0' && c <= '9' && (i == 0 || !isWordByte(line[i-1])) {
+			end := 1
+			for end < len(rest) && (isWordByte(rest[end]) || rest[end] == '.') {
+				end++
+			}
+			emit(styles.SynNumber, rest[:end])
+			i += end
+			continue
+		}
+		if isWordByte(c) && !(c >= '0' && c <= '9') {
+			end := 1
+			for end < len(rest) && isWordByte(rest[end]) {
+				end++
+			}
+			word := rest[:end]
+			switch {
+			case rules.keywords[word]:
+				emit(styles.SynKeyword, word)
+			case rules.types[word]:
+				emit(styles.SynType, word)
+			case end < len(rest) && rest[end] == '(':
+				emit(styles.SynFunc, word)
+			default:
+				plain.WriteString(word)
+			}
+			i += end
+			continue
+		}
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+	return out.String()
+}
+
+func highlightDiffLine(line string, styles uiStyles) string {
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		return styles.DiffHunk.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return styles.DiffAdd.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return styles.DiffDel.Render(line)
+	default:
+		return styles.CodeText.Render(line)
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
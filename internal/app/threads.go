@@ -0,0 +1,281 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	maxReviewThreads = 100
+	maxThreadHunk    = 4
+)
+
+type reviewThread struct {
+	ID       string
+	Path     string
+	Line     int
+	Resolved bool
+	Outdated bool
+	DiffHunk string
+	Comments []reviewComment
+}
+
+type reviewComment struct {
+	ID      int64
+	Author  string
+	Body    string
+	Created time.Time
+}
+
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $first: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: $first) {
+        nodes {
+          id
+          path
+          line
+          originalLine
+          isResolved
+          isOutdated
+          comments(first: 50) {
+            nodes {
+              databaseId
+              body
+              createdAt
+              diffHunk
+              author { login }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// fetchReviewThreads loads the inline review threads of a pull request,
+// ordered by file and line so they can be rendered grouped by file.
+func fetchReviewThreads(ctx context.Context, token string, item issueItem) ([]reviewThread, error) {
+	owner, name, ok := strings.Cut(item.Repo, "/")
+	if !ok || item.Number == 0 {
+		return nil, errors.New("missing repo or number")
+	}
+
+	var payload struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						ID           string `json:"id"`
+						Path         string `json:"path"`
+						Line         *int   `json:"line"`
+						OriginalLine *int   `json:"originalLine"`
+						IsResolved   bool   `json:"isResolved"`
+						IsOutdated   bool   `json:"isOutdated"`
+						Comments     struct {
+							Nodes []struct {
+								DatabaseID int64     `json:"databaseId"`
+								Body       string    `json:"body"`
+								CreatedAt  time.Time `json:"createdAt"`
+								DiffHunk   string    `json:"diffHunk"`
+								Author     *struct {
+									Login string `json:"login"`
+								} `json:"author"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	variables := map[string]any{
+		"owner":  owner,
+		"name":   name,
+		"number": item.Number,
+		"first":  maxReviewThreads,
+	}
+	if err := postGraphQL(ctx, token, reviewThreadsQuery, variables, &payload); err != nil {
+		return nil, err
+	}
+
+	nodes := payload.Repository.PullRequest.ReviewThreads.Nodes
+	threads := make([]reviewThread, 0, len(nodes))
+	for _, n := range nodes {
+		t := reviewThread{
+			ID:       n.ID,
+			Path:     sanitizeLine(n.Path),
+			Resolved: n.IsResolved,
+			Outdated: n.IsOutdated,
+		}
+		switch {
+		case n.Line != nil:
+			t.Line = *n.Line
+		case n.OriginalLine != nil:
+			t.Line = *n.OriginalLine
+		}
+		for _, c := range n.Comments.Nodes {
+			author := "ghost"
+			if c.Author != nil {
+				author = sanitizeLine(c.Author.Login)
+			}
+			if t.DiffHunk == "" {
+				t.DiffHunk = sanitizeText(c.DiffHunk)
+			}
+			t.Comments = append(t.Comments, reviewComment{
+				ID:      c.DatabaseID,
+				Author:  author,
+				Body:    sanitizeText(c.Body),
+				Created: c.CreatedAt,
+			})
+		}
+		threads = append(threads, t)
+	}
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].Path != threads[j].Path {
+			return threads[i].Path < threads[j].Path
+		}
+		return threads[i].Line < threads[j].Line
+	})
+	return threads, nil
+}
+
+// replyToThread answers a review thread. GitHub attaches the reply to the
+// thread of the comment it responds to, so the first comment is used.
+func replyToThread(ctx context.Context, token string, item issueItem, thread reviewThread, body string) error {
+	if item.Repo == "" || item.Number == 0 {
+		return errors.New("missing repo or number")
+	}
+	if len(thread.Comments) == 0 || thread.Comments[0].ID == 0 {
+		return errors.New("thread has no comment to reply to")
+	}
+	raw, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d/comments/%d/replies", item.Repo, item.Number, thread.Comments[0].ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(string(raw)))
+	if err != nil {
+		return err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	return nil
+}
+
+func threadIndex(threads []reviewThread, id string) int {
+	for i, t := range threads {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t reviewThread) location() string {
+	if t.Line > 0 {
+		return fmt.Sprintf("%s:%d", t.Path, t.Line)
+	}
+	return t.Path
+}
+
+// renderThreads draws review threads grouped by file. Resolved threads are
+// folded to one line unless expanded. It also returns the line, within the
+// rendered text, at which the selected thread starts (-1 if none).
+func renderThreads(threads []reviewThread, errText, selected string, expanded map[string]bool, width int, styles uiStyles) (string, int) {
+	resolved := 0
+	for _, t := range threads {
+		if t.Resolved {
+			resolved++
+		}
+	}
+	header := styles.AccentText.Render(fmt.Sprintf("Review threads (%d, %d resolved)", len(threads), resolved))
+	if errText != "" {
+		return fmt.Sprintf("%s\n  %s", styles.AccentText.Render("Review threads"), styles.MutedText.Render("unavailable: "+errText)), -1
+	}
+	if len(threads) == 0 {
+		return fmt.Sprintf("%s\n  %s", styles.AccentText.Render("Review threads"), styles.MutedText.Render("(no review threads)")), -1
+	}
+
+	lines := []string{header}
+	anchor := -1
+	lastPath := ""
+	for i, t := range threads {
+		if i == 0 || t.Path != lastPath {
+			lines = append(lines, "  "+styles.MdSubheading.Render(t.Path))
+			lastPath = t.Path
+		}
+		branch := styles.TreeLine.Render("  |- ")
+		if t.ID == selected {
+			branch = styles.RowCursor.Render("  ▶  ")
+			anchor = strings.Count(strings.Join(lines, "\n"), "\n") + 1
+		}
+
+		where := "file"
+		if t.Line > 0 {
+			where = fmt.Sprintf("line %d", t.Line)
+		}
+		count := fmt.Sprintf("%d comments", len(t.Comments))
+		if len(t.Comments) == 1 {
+			count = "1 comment"
+		}
+		meta := []string{where, count}
+		if t.Outdated {
+			meta = append(meta, "outdated")
+		}
+
+		if t.Resolved && !expanded[t.ID] {
+			summary := styles.StateOpen.Render("✓") + " " + styles.MutedText.Render("resolved • "+strings.Join(meta, " • "))
+			if len(t.Comments) > 0 {
+				first, _, _ := strings.Cut(strings.TrimSpace(t.Comments[0].Body), "\n")
+				summary += " " + styles.MetaText.Render(t.Comments[0].Author) + styles.MutedText.Render(": "+first)
+			}
+			lines = append(lines, branch+ansi.Truncate(summary, max(10, width-5), "…"))
+			continue
+		}
+
+		glyph := styles.CIPending.Render("●")
+		if t.Resolved {
+			glyph = styles.StateOpen.Render("✓")
+		}
+		lines = append(lines, branch+glyph+" "+styles.MutedText.Render(strings.Join(meta, " • ")))
+
+		rail := styles.TreeLine.Render("  |  ")
+		if hunk := strings.TrimRight(t.DiffHunk, "\n"); hunk != "" {
+			hunkLines := strings.Split(hunk, "\n")
+			if len(hunkLines) > maxThreadHunk {
+				hunkLines = hunkLines[len(hunkLines)-maxThreadHunk:]
+			}
+			for _, h := range hunkLines {
+				lines = append(lines, rail+ansi.Truncate(highlightDiffLine(h, styles), max(10, width-5), "…"))
+			}
+		}
+		for _, c := range t.Comments {
+			lines = append(lines, rail+styles.MetaText.Render(c.Author)+" "+styles.MutedText.Render("• "+humanizeSince(c.Created)))
+			body := strings.TrimSpace(c.Body)
+			rendered := styles.MutedText.Render("(empty)")
+			if body != "" {
+				rendered = renderMarkdown(body, width-7, styles)
+			}
+			lines = append(lines, prefixLines(rendered, rail+"  "))
+		}
+	}
+	return strings.Join(lines, "\n"), anchor
+}
@@ -0,0 +1,326 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	sectionComments = iota
+	sectionTimeline
+)
+
+const maxTimelineEvents = 30
+
+type timelineEvent struct {
+	Event   string
+	Actor   string
+	Created time.Time
+	Body    string
+	State   string
+	Detail  string
+	Label   issueLabel
+}
+
+type timelinePayload struct {
+	Event       string     `json:"event"`
+	CreatedAt   *time.Time `json:"created_at"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`
+	SHA         string     `json:"sha"`
+	CommitID    string     `json:"commit_id"`
+	Message     string     `json:"message"`
+	Actor       *struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Author *struct {
+		Name string    `json:"name"`
+		Date time.Time `json:"date"`
+	} `json:"author"`
+	Label *struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"label"`
+	Assignee *struct {
+		Login string `json:"login"`
+	} `json:"assignee"`
+	RequestedReviewer *struct {
+		Login string `json:"login"`
+	} `json:"requested_reviewer"`
+	RequestedTeam *struct {
+		Name string `json:"name"`
+	} `json:"requested_team"`
+	Rename *struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Source *struct {
+		Issue *struct {
+			Number     int    `json:"number"`
+			Title      string `json:"title"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		} `json:"issue"`
+	} `json:"source"`
+}
+
+func fetchTimeline(ctx context.Context, token string, item issueItem, page int) ([]timelineEvent, commentPageInfo, error) {
+	if item.Repo == "" || item.Number == 0 {
+		return nil, commentPageInfo{}, errors.New("missing repo or number")
+	}
+	endpoint, err := url.Parse(fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/timeline", item.Repo, item.Number))
+	if err != nil {
+		return nil, commentPageInfo{}, err
+	}
+	params := endpoint.Query()
+	params.Set("per_page", fmt.Sprintf("%d", maxTimelineEvents))
+	params.Set("page", fmt.Sprintf("%d", page))
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, commentPageInfo{}, err
+	}
+	addJSONHeaders(req, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, commentPageInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, commentPageInfo{}, readAPIError(resp)
+	}
+
+	var payload []timelinePayload
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, commentPageInfo{}, err
+	}
+
+	events := make([]timelineEvent, 0, len(payload))
+	for _, p := range payload {
+		if ev, ok := timelineEventFromPayload(p); ok {
+			events = append(events, ev)
+		}
+	}
+
+	pageInfo := commentPageInfo{
+		Page:    page,
+		HasNext: hasLinkRel(resp.Header.Get("Link"), "next"),
+		HasPrev: hasLinkRel(resp.Header.Get("Link"), "prev"),
+	}
+	return events, pageInfo, nil
+}
+
+func timelineEventFromPayload(p timelinePayload) (timelineEvent, bool) {
+	ev := timelineEvent{
+		Event: p.Event,
+		Body:  sanitizeText(p.Body),
+		State: strings.ToLower(sanitizeLine(p.State)),
+	}
+	switch {
+	case p.Actor != nil:
+		ev.Actor = sanitizeLine(p.Actor.Login)
+	case p.User != nil:
+		ev.Actor = sanitizeLine(p.User.Login)
+	case p.Author != nil:
+		ev.Actor = sanitizeLine(p.Author.Name)
+	}
+	switch {
+	case p.CreatedAt != nil:
+		ev.Created = *p.CreatedAt
+	case p.SubmittedAt != nil:
+		ev.Created = *p.SubmittedAt
+	case p.Author != nil:
+		ev.Created = p.Author.Date
+	}
+
+	switch p.Event {
+	case "mentioned", "subscribed", "unsubscribed":
+		return timelineEvent{}, false
+	case "committed":
+		subject, _, _ := strings.Cut(p.Message, "\n")
+		ev.Detail = fmt.Sprintf("%s %s", shortSHA(p.SHA), sanitizeLine(subject))
+	case "labeled", "unlabeled":
+		if p.Label != nil {
+			ev.Label = issueLabel{Name: sanitizeLine(p.Label.Name), Color: sanitizeLine(p.Label.Color)}
+		}
+	case "assigned", "unassigned":
+		if p.Assignee != nil {
+			ev.Detail = sanitizeLine(p.Assignee.Login)
+		}
+	case "review_requested", "review_request_removed":
+		if p.RequestedReviewer != nil {
+			ev.Detail = sanitizeLine(p.RequestedReviewer.Login)
+		} else if p.RequestedTeam != nil {
+			ev.Detail = sanitizeLine(p.RequestedTeam.Name)
+		}
+	case "renamed":
+		if p.Rename != nil {
+			ev.Detail = fmt.Sprintf("%q → %q", sanitizeLine(p.Rename.From), sanitizeLine(p.Rename.To))
+		}
+	case "milestoned", "demilestoned":
+		if p.Milestone != nil {
+			ev.Detail = sanitizeLine(p.Milestone.Title)
+		}
+	case "cross-referenced":
+		if p.Source != nil && p.Source.Issue != nil {
+			issue := p.Source.Issue
+			ev.Detail = fmt.Sprintf("%s#%d %s", sanitizeLine(issue.Repository.FullName), issue.Number, sanitizeLine(issue.Title))
+		}
+	case "referenced", "closed", "merged":
+		if p.CommitID != "" {
+			ev.Detail = shortSHA(p.CommitID)
+		}
+	}
+	return ev, true
+}
+
+func shortSHA(sha string) string {
+	sha = sanitizeLine(sha)
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func (ev timelineEvent) glyph(styles uiStyles) string {
+	switch ev.Event {
+	case "commented":
+		return styles.MdLink.UnsetUnderline().Render("●")
+	case "reviewed":
+		switch ev.State {
+		case "approved":
+			return styles.StateOpen.Render("✓")
+		case "changes_requested":
+			return styles.StateClosed.Render("✗")
+		default:
+			return styles.MetaText.Render("◎")
+		}
+	case "labeled", "unlabeled":
+		return styles.MdBullet.Render("◈")
+	case "committed":
+		return styles.MetaText.Render("⊙")
+	case "head_ref_force_pushed":
+		return styles.SynNumber.Render("⇅")
+	case "cross-referenced", "referenced", "connected":
+		return styles.DiffHunk.Render("↗")
+	case "closed":
+		return styles.StateClosed.Render("✖")
+	case "reopened":
+		return styles.StateOpen.Render("↺")
+	case "merged":
+		return styles.StateMerged.Render("⬢")
+	case "ready_for_review":
+		return styles.StateOpen.Render("◆")
+	case "convert_to_draft":
+		return styles.StateDraft.Render("◇")
+	default:
+		return styles.MutedText.Render("○")
+	}
+}
+
+func (ev timelineEvent) summary(styles uiStyles) string {
+	verb := func(text string) string { return styles.MutedText.Render(text) }
+	detail := func(text string) string { return styles.BodyText.Render(text) }
+	switch ev.Event {
+	case "commented":
+		return verb("commented")
+	case "reviewed":
+		switch ev.State {
+		case "approved":
+			return verb("approved these changes")
+		case "changes_requested":
+			return verb("requested changes")
+		case "dismissed":
+			return verb("left a review that was dismissed")
+		default:
+			return verb("reviewed")
+		}
+	case "labeled":
+		return verb("added") + " " + labelChip(ev.Label)
+	case "unlabeled":
+		return verb("removed") + " " + labelChip(ev.Label)
+	case "committed":
+		return verb("pushed") + " " + detail(ev.Detail)
+	case "head_ref_force_pushed":
+		return verb("force-pushed the branch")
+	case "cross-referenced":
+		return verb("referenced this from") + " " + detail(ev.Detail)
+	case "referenced":
+		return verb("referenced this in commit") + " " + detail(ev.Detail)
+	case "closed":
+		if ev.Detail != "" {
+			return verb("closed this in") + " " + detail(ev.Detail)
+		}
+		return verb("closed this")
+	case "reopened":
+		return verb("reopened this")
+	case "merged":
+		return verb("merged commit") + " " + detail(ev.Detail)
+	case "assigned":
+		return verb("assigned") + " " + detail(ev.Detail)
+	case "unassigned":
+		return verb("unassigned") + " " + detail(ev.Detail)
+	case "review_requested":
+		return verb("requested a review from") + " " + detail(ev.Detail)
+	case "review_request_removed":
+		return verb("removed the review request for") + " " + detail(ev.Detail)
+	case "renamed":
+		return verb("changed the title") + " " + detail(ev.Detail)
+	case "milestoned":
+		return verb("added this to") + " " + detail(ev.Detail)
+	case "demilestoned":
+		return verb("removed this from") + " " + detail(ev.Detail)
+	default:
+		return verb(strings.ReplaceAll(ev.Event, "_", " "))
+	}
+}
+
+func renderTimeline(events []timelineEvent, page int, hasNext, hasPrev bool, width int, styles uiStyles) string {
+	header := styles.AccentText.Render(fmt.Sprintf("Timeline (page %d)", page))
+	if len(events) == 0 {
+		return fmt.Sprintf("%s\n  %s", header, styles.MutedText.Render("(no events)"))
+	}
+	builder := strings.Builder{}
+	builder.WriteString(header)
+	for _, ev := range events {
+		actor := ev.Actor
+		if actor == "" {
+			actor = "someone"
+		}
+		line := fmt.Sprintf("%s %s %s %s",
+			ev.glyph(styles),
+			styles.MetaText.Render(actor),
+			ev.summary(styles),
+			styles.MutedText.Render("• "+humanizeSince(ev.Created)),
+		)
+		builder.WriteString("\n")
+		builder.WriteString(styles.TreeLine.Render("|- "))
+		builder.WriteString(lipgloss.NewStyle().MaxWidth(max(10, width-3)).Render(line))
+		body := strings.TrimSpace(ev.Body)
+		if body != "" && (ev.Event == "commented" || ev.Event == "reviewed") {
+			builder.WriteString("\n")
+			builder.WriteString(prefixLines(renderMarkdown(body, width-4, styles), styles.TreeLine.Render("|  ")))
+		}
+	}
+	builder.WriteString(pageHints(hasPrev, hasNext, styles))
+	return builder.String()
+}
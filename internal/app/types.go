@@ -33,12 +33,61 @@ const (
 	maxComments = 10
 )
 
+const (
+	composeComment = iota
+	composeThreadReply
+	composeReview
+	composeLineComment
+	composeMerge
+	composeEditComment
+	composeQuoteReply
+	composeClose
+	composeIssueField
+	composeNewIssue
+)
+
+const (
+	pickNone = iota
+	pickLabels
+	pickAssignees
+	pickReviewers
+	pickDrafts
+	pickReplies
+	pickSaveReply
+	pickDuplicate
+	pickIssueRepo
+	pickIssueTemplate
+	pickIssueField
+)
+
+const (
+	confirmState = iota
+	confirmReview
+	confirmDiscardReview
+	confirmMerge
+	confirmDisableAutoMerge
+	confirmDeleteComment
+)
+
+type issueLabel struct {
+	Name        string
+	Color       string
+	Description string
+}
+
 type issueItem struct {
 	TitleText string
 	Repo      string
 	Number    int
 	URL       string
 	Kind      string
+	State     string
+	Draft     bool
+	Author    string
+	Labels    []issueLabel
+	Comments  int
+	Updated   time.Time
+	CI        string
 }
 
 func (i issueItem) Title() string { return i.TitleText }
@@ -63,10 +112,16 @@ type detail struct {
 	Repo            string
 	Number          int
 	Kind            string
-	Labels          []string
+	Labels          []issueLabel
 	Assignees       []string
 	Draft           bool
 	Mergeable       *bool
+	MergeableState  string
+	NodeID          string
+	AutoMerge       *autoMergeState
+	HeadSHA         string
+	HeadRef         string
+	HeadRepo        string
 	Additions       int
 	Deletions       int
 	ChangedFiles    int
@@ -74,18 +129,43 @@ type detail struct {
 	ReviewApprovals int
 	ReviewChanges   int
 	ReviewComments  int
+	Checks          []checkRun
+	ChecksError     string
+	Threads         []reviewThread
+	ThreadsError    string
+	Reviewers       []reviewerState
 	CommentList     []issueComment
 	CommentPage     int
 	HasNextComments bool
 	HasPrevComments bool
 }
 
+type checksResult struct {
+	heads   map[string]ciHead
+	updated map[string]time.Time
+}
+
 type detailResult struct {
-	item detail
-	err  error
+	target issueItem
+	item   detail
+	err    error
+}
+
+type timelineResult struct {
+	target issueItem
+	events []timelineEvent
+	page   commentPageInfo
+	err    error
+}
+
+type diffResult struct {
+	target issueItem
+	files  []diffFile
+	err    error
 }
 
 type issueComment struct {
+	ID      int64
 	Author  string
 	Body    string
 	Updated time.Time
@@ -95,11 +175,70 @@ type commentResult struct {
 	err error
 }
 
-type stateResult struct {
-	state string
+type commentEditResult struct {
+	deleted bool
+	err     error
+}
+
+type reviewResult struct {
+	event string
 	err   error
 }
 
+type mergeOptionsResult struct {
+	repo    string
+	options mergeOptions
+	err     error
+}
+
+type mergeResult struct {
+	method  string
+	outcome mergeOutcome
+	err     error
+}
+
+type autoMergeResult struct {
+	method string
+	err    error
+}
+
+type labelsResult struct {
+	repo   string
+	labels []issueLabel
+	err    error
+}
+
+type labelsSetResult struct {
+	target   issueItem
+	labels   []issueLabel
+	previous []issueLabel
+	err      error
+}
+
+type viewerResult struct {
+	login string
+	err   error
+}
+
+type peopleResult struct {
+	repo  string
+	users []string
+	teams []string
+	err   error
+}
+
+type peopleUpdatedResult struct {
+	target  issueItem
+	message string
+	err     error
+}
+
+type stateResult struct {
+	state  string
+	change stateChange
+	err    error
+}
+
 type commentPageInfo struct {
 	Page    int
 	HasNext bool
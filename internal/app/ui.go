@@ -7,52 +7,105 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type uiStyles struct {
-	Title      lipgloss.Style
-	Filter     lipgloss.Style
-	HelpKey    lipgloss.Style
-	HelpText   lipgloss.Style
-	Status     lipgloss.Style
-	StatusErr  lipgloss.Style
-	BodyText   lipgloss.Style
-	MetaText   lipgloss.Style
-	MutedText  lipgloss.Style
-	AccentText lipgloss.Style
-	TreeLine   lipgloss.Style
-	Panel      lipgloss.Style
-	Confirm    lipgloss.Style
+	Title               lipgloss.Style
+	Filter              lipgloss.Style
+	HelpKey             lipgloss.Style
+	HelpText            lipgloss.Style
+	Status              lipgloss.Style
+	StatusErr           lipgloss.Style
+	BodyText            lipgloss.Style
+	MetaText            lipgloss.Style
+	MutedText           lipgloss.Style
+	AccentText          lipgloss.Style
+	TreeLine            lipgloss.Style
+	Panel               lipgloss.Style
+	Confirm             lipgloss.Style
+	GroupHeader         lipgloss.Style
+	GroupHeaderSelected lipgloss.Style
+	RowTitle            lipgloss.Style
+	RowTitleSelected    lipgloss.Style
+	RowCursor           lipgloss.Style
+	RowAuthor           lipgloss.Style
+	StateOpen           lipgloss.Style
+	StateClosed         lipgloss.Style
+	StateMerged         lipgloss.Style
+	StateDraft          lipgloss.Style
+	CIPending           lipgloss.Style
+	CodeText            lipgloss.Style
+	SynKeyword          lipgloss.Style
+	SynType             lipgloss.Style
+	SynFunc             lipgloss.Style
+	SynString           lipgloss.Style
+	SynNumber           lipgloss.Style
+	SynComment          lipgloss.Style
+	DiffAdd             lipgloss.Style
+	DiffDel             lipgloss.Style
+	DiffHunk            lipgloss.Style
+	MdHeading           lipgloss.Style
+	MdSubheading        lipgloss.Style
+	MdQuote             lipgloss.Style
+	MdQuoteBar          lipgloss.Style
+	MdCode              lipgloss.Style
+	MdLink              lipgloss.Style
+	MdMention           lipgloss.Style
+	MdBullet            lipgloss.Style
+	MdTableHeader       lipgloss.Style
 }
 
 func newStyles() uiStyles {
 	return uiStyles{
-		Title:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1e66f5")),
-		Filter:     lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")).Bold(true),
-		HelpKey:    lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Bold(true),
-		HelpText:   lipgloss.NewStyle().Foreground(lipgloss.Color("#6c6f85")),
-		Status:     lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")),
-		StatusErr:  lipgloss.NewStyle().Foreground(lipgloss.Color("#d20f39")).Bold(true),
-		BodyText:   lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")),
-		MetaText:   lipgloss.NewStyle().Foreground(lipgloss.Color("#179299")).Bold(true),
-		MutedText:  lipgloss.NewStyle().Foreground(lipgloss.Color("#8c8fa1")),
-		AccentText: lipgloss.NewStyle().Foreground(lipgloss.Color("#ea76cb")).Bold(true),
-		TreeLine:   lipgloss.NewStyle().Foreground(lipgloss.Color("#bcc0cc")),
-		Panel:      lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#bcc0cc")).Padding(0, 1),
-		Confirm:    lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).BorderForeground(lipgloss.Color("#df8e1d")).Padding(1, 2),
-	}
-}
-
-func initList() list.Model {
-	delegate := list.NewDefaultDelegate()
-	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(lipgloss.Color("#1e66f5")).Bold(true)
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(lipgloss.Color("#5c5f77"))
-	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.Foreground(lipgloss.Color("#4c4f69"))
-	delegate.Styles.NormalDesc = delegate.Styles.NormalDesc.Foreground(lipgloss.Color("#8c8fa1"))
-	delegate.Styles.DimmedTitle = delegate.Styles.DimmedTitle.Foreground(lipgloss.Color("#9ca0b0"))
-	delegate.Styles.DimmedDesc = delegate.Styles.DimmedDesc.Foreground(lipgloss.Color("#9ca0b0"))
-
-	l := list.New([]list.Item{}, delegate, 0, 0)
+		Title:               lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1e66f5")),
+		Filter:              lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")).Bold(true),
+		HelpKey:             lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Bold(true),
+		HelpText:            lipgloss.NewStyle().Foreground(lipgloss.Color("#6c6f85")),
+		Status:              lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")),
+		StatusErr:           lipgloss.NewStyle().Foreground(lipgloss.Color("#d20f39")).Bold(true),
+		BodyText:            lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")),
+		MetaText:            lipgloss.NewStyle().Foreground(lipgloss.Color("#179299")).Bold(true),
+		MutedText:           lipgloss.NewStyle().Foreground(lipgloss.Color("#8c8fa1")),
+		AccentText:          lipgloss.NewStyle().Foreground(lipgloss.Color("#ea76cb")).Bold(true),
+		TreeLine:            lipgloss.NewStyle().Foreground(lipgloss.Color("#bcc0cc")),
+		Panel:               lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#bcc0cc")).Padding(0, 1),
+		Confirm:             lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).BorderForeground(lipgloss.Color("#df8e1d")).Padding(1, 2),
+		GroupHeader:         lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")).Bold(true),
+		GroupHeaderSelected: lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Bold(true).Underline(true),
+		RowTitle:            lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")),
+		RowTitleSelected:    lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Bold(true),
+		RowCursor:           lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")),
+		RowAuthor:           lipgloss.NewStyle().Foreground(lipgloss.Color("#7287fd")),
+		StateOpen:           lipgloss.NewStyle().Foreground(lipgloss.Color("#40a02b")).Bold(true),
+		StateClosed:         lipgloss.NewStyle().Foreground(lipgloss.Color("#d20f39")).Bold(true),
+		StateMerged:         lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")).Bold(true),
+		StateDraft:          lipgloss.NewStyle().Foreground(lipgloss.Color("#9ca0b0")),
+		CIPending:           lipgloss.NewStyle().Foreground(lipgloss.Color("#df8e1d")).Bold(true),
+		CodeText:            lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")),
+		SynKeyword:          lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")),
+		SynType:             lipgloss.NewStyle().Foreground(lipgloss.Color("#df8e1d")),
+		SynFunc:             lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")),
+		SynString:           lipgloss.NewStyle().Foreground(lipgloss.Color("#40a02b")),
+		SynNumber:           lipgloss.NewStyle().Foreground(lipgloss.Color("#fe640b")),
+		SynComment:          lipgloss.NewStyle().Foreground(lipgloss.Color("#9ca0b0")).Italic(true),
+		DiffAdd:             lipgloss.NewStyle().Foreground(lipgloss.Color("#40a02b")),
+		DiffDel:             lipgloss.NewStyle().Foreground(lipgloss.Color("#d20f39")),
+		DiffHunk:            lipgloss.NewStyle().Foreground(lipgloss.Color("#04a5e5")).Bold(true),
+		MdHeading:           lipgloss.NewStyle().Foreground(lipgloss.Color("#ea76cb")).Bold(true),
+		MdSubheading:        lipgloss.NewStyle().Foreground(lipgloss.Color("#179299")).Bold(true),
+		MdQuote:             lipgloss.NewStyle().Foreground(lipgloss.Color("#6c6f85")),
+		MdQuoteBar:          lipgloss.NewStyle().Foreground(lipgloss.Color("#bcc0cc")),
+		MdCode:              lipgloss.NewStyle().Foreground(lipgloss.Color("#d20f39")).Background(lipgloss.Color("#e6e9ef")),
+		MdLink:              lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Underline(true),
+		MdMention:           lipgloss.NewStyle().Foreground(lipgloss.Color("#1e66f5")).Bold(true),
+		MdBullet:            lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")),
+		MdTableHeader:       lipgloss.NewStyle().Foreground(lipgloss.Color("#4c4f69")).Bold(true),
+	}
+}
+
+func initList(styles uiStyles) list.Model {
+	l := list.New([]list.Item{}, newItemDelegate(styles), 0, 0)
 	l.SetShowStatusBar(false)
 	l.SetShowFilter(false)
 	l.SetShowHelp(false)
@@ -76,42 +129,112 @@ func renderTabs(tabList []tab, active int, styles uiStyles) string {
 	return strings.Join(parts, " ")
 }
 
-func renderComments(comments []issueComment, page int, hasNext, hasPrev bool, width int, styles uiStyles) string {
+// renderComments also returns the line at which the selected comment starts
+// (-1 if none).
+func renderComments(comments []issueComment, page int, hasNext, hasPrev bool, selected int64, width int, styles uiStyles) (string, int) {
 	if len(comments) == 0 {
-		return fmt.Sprintf("%s\n  %s", styles.AccentText.Render("Comments"), styles.MutedText.Render("(no comments)"))
+		return fmt.Sprintf("%s\n  %s", styles.AccentText.Render("Comments"), styles.MutedText.Render("(no comments)")), -1
 	}
 	builder := strings.Builder{}
-	bodyStyle := styles.BodyText.Copy().Width(width - 4)
 	builder.WriteString(styles.AccentText.Render(fmt.Sprintf("Comments (page %d)", page)))
+	anchor := -1
 	for _, c := range comments {
 		builder.WriteString("\n")
-		builder.WriteString(styles.TreeLine.Render("|- "))
-		builder.WriteString(styles.MetaText.Render(c.Author))
-		builder.WriteString(" ")
-		builder.WriteString(styles.MutedText.Render("• " + humanizeSince(c.Updated)))
-		body := strings.TrimSpace(c.Body)
-		if body == "" {
-			body = "(empty)"
+		isSelected := selected != 0 && c.ID == selected
+		if isSelected {
+			anchor = strings.Count(builder.String(), "\n")
 		}
-		bodyRendered := bodyStyle.Render(body)
-		builder.WriteString("\n")
-		builder.WriteString(prefixLines(bodyRendered, styles.TreeLine.Render("|  ")))
+		builder.WriteString(renderComment(c, isSelected, width, styles))
+	}
+	builder.WriteString(pageHints(hasPrev, hasNext, styles))
+	return builder.String(), anchor
+}
+
+// renderComment draws one comment as it appears in the comment list.
+func renderComment(c issueComment, selected bool, width int, styles uiStyles) string {
+	branch := styles.TreeLine.Render("|- ")
+	if selected {
+		branch = styles.RowCursor.Render("▶  ")
+	}
+	header := branch + styles.MetaText.Render(c.Author) + " " + styles.MutedText.Render("• "+humanizeSince(c.Updated))
+	body := strings.TrimSpace(c.Body)
+	bodyRendered := styles.MutedText.Render("(empty)")
+	if body != "" {
+		bodyRendered = renderMarkdown(body, width-4, styles)
 	}
-	if hasPrev || hasNext {
-		builder.WriteString("\n|")
+	return header + "\n" + prefixLines(bodyRendered, styles.TreeLine.Render("|  "))
+}
+
+func pageHints(hasPrev, hasNext bool, styles uiStyles) string {
+	if !hasPrev && !hasNext {
+		return ""
+	}
+	builder := strings.Builder{}
+	builder.WriteString("\n|")
+	if hasPrev {
+		builder.WriteString(styles.MutedText.Render("  prev: p"))
+	}
+	if hasNext {
 		if hasPrev {
-			builder.WriteString(styles.MutedText.Render("  prev: p"))
+			builder.WriteString("  ")
 		}
-		if hasNext {
-			if hasPrev {
-				builder.WriteString("  ")
+		builder.WriteString(styles.MutedText.Render("next: n"))
+	}
+	return builder.String()
+}
+
+func renderChecks(checks []checkRun, errText string, width int, styles uiStyles) string {
+	header := styles.AccentText.Render("Checks")
+	if errText != "" {
+		return fmt.Sprintf("%s\n  %s", header, styles.MutedText.Render("unavailable: "+errText))
+	}
+	if len(checks) == 0 {
+		return fmt.Sprintf("%s\n  %s", header, styles.MutedText.Render("(no checks)"))
+	}
+	nameWidth := 0
+	for _, c := range checks {
+		nameWidth = max(nameWidth, lipgloss.Width(c.Name))
+	}
+	nameWidth = min(nameWidth, max(10, width/3))
+
+	builder := strings.Builder{}
+	builder.WriteString(header)
+	for _, c := range checks {
+		conclusion := c.Conclusion
+		if c.ciState() == ciPending {
+			conclusion = strings.ReplaceAll(c.Status, "_", " ")
+			if conclusion == "" {
+				conclusion = "pending"
 			}
-			builder.WriteString(styles.MutedText.Render("next: n"))
 		}
+		line := fmt.Sprintf("%s %s  %s  %s",
+			ciGlyph(c.ciState(), styles),
+			padCell(c.Name, nameWidth, false),
+			padCell(conclusion, 11, false),
+			padCell(formatDuration(c.duration()), 7, true),
+		)
+		if c.URL != "" {
+			line += "  " + styles.MutedText.Render(c.URL)
+		}
+		builder.WriteString("\n  ")
+		builder.WriteString(ansi.Truncate(line, max(10, width-2), "…"))
 	}
 	return builder.String()
 }
 
+func ciGlyph(state string, styles uiStyles) string {
+	switch state {
+	case ciSuccess:
+		return styles.StateOpen.Render("✓")
+	case ciFailure:
+		return styles.StateClosed.Render("✗")
+	case ciPending:
+		return styles.CIPending.Render("●")
+	default:
+		return " "
+	}
+}
+
 func prefixLines(text, prefix string) string {
 	lines := strings.Split(text, "\n")
 	for i, line := range lines {
@@ -150,6 +273,27 @@ func humanizeSince(t time.Time) string {
 	}
 }
 
+func shortAge(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo", int(d.Hours()/24/30))
+	default:
+		return fmt.Sprintf("%dy", int(d.Hours()/24/365))
+	}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a